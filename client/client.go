@@ -1,25 +1,33 @@
 package client
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
+	"sync"
+	"sync/atomic"
 
 	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/db"
 	"github.com/yassine-manai/go_zr_sdk/internal/errors"
 	internalhttp "github.com/yassine-manai/go_zr_sdk/internal/http"
 	"github.com/yassine-manai/go_zr_sdk/internal/logger"
 	"github.com/yassine-manai/go_zr_sdk/ui/customer_media/contract"
 	"github.com/yassine-manai/go_zr_sdk/ui/customer_media/participant"
+	"github.com/yassine-manai/go_zr_sdk/ui/rebate"
+	"github.com/yassine-manai/go_zr_sdk/ui/shift"
+	ticketclass "github.com/yassine-manai/go_zr_sdk/ui/ticket_class"
 )
 
 // Client is the main SDK client
 type Client struct {
-	config     *config.Config // external config
-	httpClient *http.Client   // httpConnection helper
-	dbConn     *sql.DB        // dbConnection helper
-	logger     logger.Logger  // log Handler
-	UI         UI             // ZR UI's Handler
-	DB         DB             // ZR DB Handler
+	config     atomic.Pointer[config.Config] // external config, replaced by ApplyConfig
+	reloadMu   sync.Mutex                    // Serializes ApplyConfig
+	httpClient *http.Client                  // httpConnection helper
+	uiClient   *internalhttp.Client          // ZR UI's request helper
+	logger     logger.Logger                 // log Handler
+	UI         UI                            // ZR UI's Handler
+	DB         DB                            // ZR DB Handler
 }
 
 // UI Strct
@@ -28,13 +36,20 @@ type UI struct {
 		Contract    *contract.ContractService       // Contract Service
 		Participant *participant.ParticipantService // Participants Service
 	}
+	TicketClass *ticketclass.TicketClassService // Ticket Class Service
+	Rebate      *rebate.RebateService           // Rebate Service
+	Shift       *shift.ShiftService             // Shift Service
 }
 
-// DB stct
-type DB struct{}
+// DB gives direct read access to the ZR database. Its connection is opened on first use.
+type DB struct {
+	*db.DB                                 // Connection handle, nil when the database is not configured
+	Contracts    *db.ContractRepository    // Contract read repository
+	Participants *db.ParticipantRepository // Participant read repository
+}
 
 // New creates a new SDK client
-func NewZRClient(cfg *config.Config) (*Client, error) {
+func NewZRClient(cfg *config.Config, opts ...Option) (*Client, error) {
 
 	if cfg == nil {
 		return nil, errors.NewSDKError(errors.ErrorTypeValidation, "config cannot be nil", nil)
@@ -45,33 +60,51 @@ func NewZRClient(cfg *config.Config) (*Client, error) {
 		return nil, errors.NewSDKError(errors.ErrorTypeValidation, "invalid configuration", err)
 	}
 
+	options := &clientOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	// ================# init LOGGER helper #=====================//
 	log := createLogger(cfg)
 
-	// Create database connection
-	/*dbConn, err := createDBConnection(cfg, log)
+	// ================# init DB handle (lazy) #=====================//
+	zrDB, err := createDB(cfg, options, log)
 	if err != nil {
-		return nil, errors.NewSDKError(
-			errors.ErrorTypeDatabase,
-			"failed to create database connection",
-			err,
-		)
-	}*/
+		return nil, err
+	}
 
 	// ================# init HTTP client/helper #=====================//
 	httpClient := createHTTPClient(cfg)
 	internalHTTPClient := internalhttp.NewClient(httpClient, cfg, log)
+	if err := internalHTTPClient.ResolvePassword(context.Background()); err != nil {
+		log.Error("failed to resolve UI password", logger.Error(err))
+		return nil, errors.NewAuthenticationError("failed to resolve UI password", err)
+	}
 
 	client := &Client{
-		config:     cfg,
 		httpClient: httpClient,
-		//dbConn:     dbConn,
-		logger: log,
+		uiClient:   internalHTTPClient,
+		logger:     log,
 	}
+	client.config.Store(cfg)
+
+	client.DB.DB = zrDB
+	client.DB.Contracts = db.NewContractRepository(zrDB)
+	client.DB.Participants = db.NewParticipantRepository(zrDB)
 
 	// ================# init Services #=====================//
 	client.UI.CustomerMedia.Contract = contract.NewContractService(internalHTTPClient, log)
+	client.UI.CustomerMedia.Contract.SetHardDeleteProtection(cfg.UI.ProtectHardDelete)
 	client.UI.CustomerMedia.Participant = participant.NewParticipantService(internalHTTPClient, log)
+	if zrDB != nil {
+		client.UI.CustomerMedia.Contract.SetReferenceLookup(client.DB.Contracts.FindByReference)
+		client.UI.CustomerMedia.Contract.SetArchiveLookup(client.DB.Contracts.List)
+		client.UI.CustomerMedia.Participant.SetOwnerLookup(client.DB.Participants.FindOwner)
+	}
+	client.UI.TicketClass = ticketclass.NewTicketClassService(internalHTTPClient, log)
+	client.UI.Rebate = rebate.NewRebateService(internalHTTPClient, log)
+	client.UI.Shift = shift.NewShiftService(internalHTTPClient, log)
 	// =====================================================//
 
 	log.Info("SDK client initialized successfully", logger.String("ui_host", cfg.UI.Host))
@@ -79,22 +112,21 @@ func NewZRClient(cfg *config.Config) (*Client, error) {
 	return client, nil
 }
 
+// Capabilities reports the detected ZR database schema version and the features available on it.
+// Detection runs when the database connection is opened (first query or DB.Conn).
+func (c *Client) Capabilities() db.Capabilities {
+	return c.DB.DB.Capabilities()
+}
+
 // Close closes all connections
 func (c *Client) Close() error {
 	c.logger.Info("closing SDK client") // Fixed: c.logger not c.log
 
-	if c.dbConn != nil {
-		if err := c.dbConn.Close(); err != nil {
-			c.logger.Error("failed to close database connection", // Fixed: c.logger
-				logger.Error(err), // Fixed: logger.Error not log.Error
-			)
-			return errors.NewDatabaseError(
-				"failed to close database connection",
-				"",
-				"CLOSE",
-				err,
-			)
-		}
+	if err := c.DB.DB.Close(); err != nil {
+		c.logger.Error("failed to close database connection", // Fixed: c.logger
+			logger.Error(err), // Fixed: logger.Error not log.Error
+		)
+		return err
 	}
 
 	c.logger.Info("SDK client closed successfully") // Fixed: c.logger
@@ -106,9 +138,11 @@ func (c *Client) HTTPClient() *http.Client {
 	return c.httpClient
 }
 
-// DBConnection returns the underlying database connection (useful for advanced users)
+// DBConnection returns the underlying database connection (useful for advanced users).
+// It is nil until the connection has been opened by a first query. Statements run on it
+// bypass query logging and the read-only mode of an unsupported ZR schema.
 func (c *Client) DBConnection() *sql.DB {
-	return c.dbConn
+	return c.DB.Raw()
 }
 
 // Logger returns the logger instance
@@ -116,9 +150,9 @@ func (c *Client) Logger() logger.Logger { // Fixed: proper return type and metho
 	return c.logger
 }
 
-// Config returns the client configuration
+// Config returns the client configuration. Secret references are returned as configured, never resolved.
 func (c *Client) Config() *config.Config {
-	return c.config
+	return c.config.Load()
 }
 
 // createLogger creates a logger based on config
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/db"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// stubDriver answers the schema version query and returns contract IDs for every other query
+type stubDriver struct{ queries []string }
+
+func (d *stubDriver) Open(string) (driver.Conn, error)             { return &stubConn{d: d}, nil }
+func (d *stubDriver) Connect(context.Context) (driver.Conn, error) { return &stubConn{d: d}, nil }
+func (d *stubDriver) Driver() driver.Driver                        { return d }
+
+type stubConn struct{ d *stubDriver }
+
+func (c *stubConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+func (c *stubConn) Close() error              { return nil }
+func (c *stubConn) Begin() (driver.Tx, error) { return nil, errors.New("transactions not supported") }
+
+func (c *stubConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if strings.Contains(query, "DBVERSION") {
+		return &stubRows{cols: []string{"VERSION"}, rows: [][]driver.Value{{"12.4.1-b3"}}}, nil
+	}
+	c.d.queries = append(c.d.queries, query)
+	return &stubRows{cols: []string{"CONTRACTID"}, rows: [][]driver.Value{{int64(7)}}}, nil
+}
+
+type stubRows struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (r *stubRows) Columns() []string { return r.cols }
+func (r *stubRows) Close() error      { return nil }
+
+func (r *stubRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func testConfig() *config.Config {
+	return &config.Config{
+		UI:      config.UIConfig{Host: "zr.example", Username: "user", Password: "pass", Timeout: 5 * time.Second},
+		Timeout: 5 * time.Second,
+	}
+}
+
+func TestNewZRClientWithDBConn(t *testing.T) {
+	drv := &stubDriver{}
+	conn := sql.OpenDB(drv)
+	t.Cleanup(func() { conn.Close() })
+
+	c, err := NewZRClient(testConfig(), WithDBConn(conn, db.DialectOracle))
+	if err != nil {
+		t.Fatalf("NewZRClient: %v", err)
+	}
+
+	ids, ok, err := c.DB.Contracts.FindByReference(context.Background(), models.ContractRefMemo, "crm-1")
+	if err != nil || !ok || len(ids) != 1 || ids[0] != 7 {
+		t.Fatalf("FindByReference: %v, %v, %v", ids, ok, err)
+	}
+	if len(drv.queries) != 1 || !strings.Contains(drv.queries[0], "MEMO") {
+		t.Fatalf("unexpected queries %q", drv.queries)
+	}
+	if !c.Capabilities().Detected {
+		t.Fatal("schema version not detected on the injected connection")
+	}
+
+	// The caller keeps ownership of the injected connection
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := conn.PingContext(context.Background()); err != nil {
+		t.Fatalf("injected connection closed by the client: %v", err)
+	}
+}
+
+func TestNewZRClientDBWithoutDriver(t *testing.T) {
+	cfg := testConfig()
+	cfg.DB = config.DBConfig{Dialect: "postgres", Host: "zr-db", Port: 5432, Database: "zr", Username: "zr", Password: "pass", SSLMode: true}
+
+	c, err := NewZRClient(cfg)
+	if err != nil {
+		t.Fatalf("NewZRClient: %v", err)
+	}
+	if c.DB.Dialect() != db.DialectPostgres {
+		t.Fatalf("dialect %q", c.DB.Dialect())
+	}
+	if conn := c.DBConnection(); conn != nil {
+		t.Fatal("connection opened before first use")
+	}
+}
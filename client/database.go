@@ -2,50 +2,60 @@ package client
 
 import (
 	"database/sql"
-	"fmt"
 
 	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/db"
 	"github.com/yassine-manai/go_zr_sdk/internal/logger"
 )
 
-// TODO : TO BE CHANGED TO ORACLE
-// createDBConnection creates and configures database connection
-func createDBConnection(cfg *config.Config, log logger.Logger) (*sql.DB, error) {
-	// Build connection string
-	connStr := buildConnectionString(cfg.DB)
+// Option customizes client creation
+type Option func(*clientOptions)
 
-	log.Debug("connecting to database",
-		logger.String("host", cfg.DB.Host),
-		logger.Int("port", cfg.DB.Port),
-		logger.String("database", cfg.DB.Database),
-	)
+// clientOptions holds the values set by Options
+type clientOptions struct {
+	dbConn    *sql.DB
+	dbDialect db.Dialect
+	dbMetrics db.MetricsHook
+}
 
-	// Open connection
-	db, err := sql.Open("postgres", connStr)
-	if err != nil {
-		return nil, err
+// WithDBConn makes the client use an existing database connection instead of opening one
+// from the configuration, e.g. sqlite or a fake driver in tests. The caller keeps ownership of conn.
+func WithDBConn(conn *sql.DB, dialect db.Dialect) Option {
+	return func(o *clientOptions) {
+		o.dbConn = conn
+		o.dbDialect = dialect
 	}
+}
 
-	// Test connection
-	if err := db.Ping(); err != nil {
-		db.Close()
-		return nil, err
+// WithDBMetricsHook passes the stats of every database statement run by the SDK to hook
+func WithDBMetricsHook(hook db.MetricsHook) Option {
+	return func(o *clientOptions) {
+		o.dbMetrics = hook
 	}
-
-	log.Info("database connection established")
-
-	return db, nil
 }
 
-// buildConnectionString constructs PostgreSQL connection string
-func buildConnectionString(cfg config.DBConfig) string {
-	return fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host,
-		cfg.Port,
-		cfg.Username,
-		cfg.Password,
-		cfg.Database,
-		cfg.SSLMode,
+// createDB creates the database handle, nil when the database is neither configured nor injected.
+// No connection is made here, it is opened on first use.
+func createDB(cfg *config.Config, opts *clientOptions, log logger.Logger) (*db.DB, error) {
+	var (
+		zrDB *db.DB
+		err  error
 	)
+
+	switch {
+	case opts.dbConn != nil:
+		log.Debug("using injected database connection", logger.String("dialect", string(opts.dbDialect)))
+		zrDB = db.NewFromConn(opts.dbConn, opts.dbDialect, cfg.DB, log)
+	case cfg.DB.IsConfigured():
+		zrDB, err = db.Open(cfg.DB, log)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+
+	zrDB.SetMetricsHook(opts.dbMetrics)
+
+	return zrDB, nil
 }
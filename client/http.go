@@ -9,15 +9,10 @@ import (
 	"github.com/yassine-manai/go_zr_sdk/config"
 )
 
-// createHTTPClient creates a configured HTTP client
+// createHTTPClient creates a configured HTTP client.
+// The request timeout is applied per request from the configuration, so it can change at runtime.
 func createHTTPClient(cfg *config.Config) *http.Client {
-	timeout := cfg.Timeout
-	if cfg.UI.Timeout > 0 {
-		timeout = cfg.UI.Timeout
-	}
-
 	return &http.Client{
-		Timeout: timeout,
 		Transport: &http.Transport{
 			// Connection pooling
 			MaxIdleConns:        100,
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+)
+
+// ApplyConfig validates cfg and applies the settings that can change on a running client:
+// log level, timeouts, UI rate limit, UI credentials, hard delete protection, database statement
+// timeout, query logging and pool limits. A UI password secret reference is resolved again.
+// Other changes (hosts, TLS, database connection, logger output) need a new client: they are
+// logged and ignored, and Config keeps reporting the values in use.
+// An invalid cfg is rejected as a whole and the client keeps its configuration.
+// Concurrent calls are applied one after the other.
+func (c *Client) ApplyConfig(cfg *config.Config) error {
+	if cfg == nil {
+		return errors.NewSDKError(errors.ErrorTypeValidation, "config cannot be nil", nil)
+	}
+
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	if err := cfg.Validate(); err != nil {
+		c.logger.Error("configuration rejected", logger.Error(err))
+		return errors.NewSDKError(errors.ErrorTypeValidation, "invalid configuration", err)
+	}
+
+	current := c.config.Load()
+	next := *cfg
+
+	if restart := keepRestartSettings(&next, current); len(restart) > 0 {
+		c.logger.Warn("configuration changes ignored until the client is recreated",
+			logger.String("settings", strings.Join(restart, ", ")),
+		)
+	}
+
+	// A new UI password is resolved before anything is applied, a broken reference rejects cfg
+	password, err := config.ResolveSecret(context.Background(), next.UI.Password)
+	if err != nil {
+		c.logger.Error("configuration rejected", logger.Error(err))
+		return errors.NewAuthenticationError("failed to resolve UI password", err)
+	}
+
+	if next.Logger.Level != current.Logger.Level {
+		if setter, ok := c.logger.(logger.LevelSetter); ok {
+			level, err := logger.ParseLevel(next.Logger.Level)
+			if err != nil {
+				level = logger.LevelInfo
+			}
+			setter.SetLevel(level)
+		}
+	}
+
+	c.config.Store(&next)
+	c.uiClient.SetConfig(&next)
+	c.uiClient.SetPassword(password)
+	c.UI.CustomerMedia.Contract.SetHardDeleteProtection(next.UI.ProtectHardDelete)
+	c.DB.DB.SetConfig(next.DB)
+
+	c.logger.Info("configuration applied")
+
+	return nil
+}
+
+// WatchConfig reloads the configuration file at path when it changes and applies it with ApplyConfig.
+// Files that fail to load or validate are logged and leave the configuration unchanged.
+// onReload, when not nil, receives the outcome of every reload: nil once applied, else the
+// load or ApplyConfig error. Stop the returned watcher to stop watching.
+func (c *Client) WatchConfig(path string, interval time.Duration, onReload func(error)) (*config.Watcher, error) {
+	c.logger.Info("watching configuration file", logger.String("path", path))
+
+	watcher, err := config.Watch(path, interval, func(cfg *config.Config, err error) {
+		if err != nil {
+			c.logger.Error("failed to reload configuration file", logger.String("path", path), logger.Error(err))
+		} else {
+			c.logger.Info("configuration file changed", logger.String("path", path))
+			err = c.ApplyConfig(cfg)
+		}
+
+		if onReload != nil {
+			onReload(err)
+		}
+	})
+	if err != nil {
+		c.logger.Error("failed to watch configuration file", logger.String("path", path), logger.Error(err))
+		return nil, errors.NewSDKError(errors.ErrorTypeValidation, "failed to watch configuration file", err)
+	}
+
+	return watcher, nil
+}
+
+// keepRestartSettings copies into next the settings of current that cannot change on a running
+// client, and returns the names of those that differed
+func keepRestartSettings(next, current *config.Config) []string {
+	var changed []string
+
+	keep := func(name string, differs bool) {
+		if differs {
+			changed = append(changed, name)
+		}
+	}
+
+	keep("ui.host", next.UI.Host != current.UI.Host)
+	keep("ui.base_path", next.UI.BasePath != current.UI.BasePath)
+	keep("ui.insecure_skip_verify", next.UI.InsecureSkipVerify != current.UI.InsecureSkipVerify)
+	next.UI.Host = current.UI.Host
+	next.UI.BasePath = current.UI.BasePath
+	next.UI.InsecureSkipVerify = current.UI.InsecureSkipVerify
+
+	keep("db.driver", next.DB.Driver != current.DB.Driver)
+	keep("db.dialect", next.DB.Dialect != current.DB.Dialect)
+	keep("db.host", next.DB.Host != current.DB.Host)
+	keep("db.port", next.DB.Port != current.DB.Port)
+	keep("db.database", next.DB.Database != current.DB.Database)
+	keep("db.username", next.DB.Username != current.DB.Username)
+	keep("db.password", next.DB.Password != current.DB.Password)
+	keep("db.ssl_mode", next.DB.SSLMode != current.DB.SSLMode)
+	keep("db.ssl_policy", next.DB.SSLPolicy != current.DB.SSLPolicy)
+	keep("db.health_check_interval", next.DB.HealthCheckInterval != current.DB.HealthCheckInterval)
+	keep("db.schema_check", next.DB.SchemaCheck != current.DB.SchemaCheck)
+	next.DB.Driver = current.DB.Driver
+	next.DB.Dialect = current.DB.Dialect
+	next.DB.Host = current.DB.Host
+	next.DB.Port = current.DB.Port
+	next.DB.Database = current.DB.Database
+	next.DB.Username = current.DB.Username
+	next.DB.Password = current.DB.Password
+	next.DB.SSLMode = current.DB.SSLMode
+	next.DB.SSLPolicy = current.DB.SSLPolicy
+	next.DB.HealthCheckInterval = current.DB.HealthCheckInterval
+	next.DB.SchemaCheck = current.DB.SchemaCheck
+
+	keep("logger.enabled", next.Logger.Enabled != current.Logger.Enabled)
+	keep("logger.pretty_print", next.Logger.PrettyPrint != current.Logger.PrettyPrint)
+	next.Logger.Enabled = current.Logger.Enabled
+	next.Logger.PrettyPrint = current.Logger.PrettyPrint
+
+	return changed
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+)
+
+func TestApplyConfig(t *testing.T) {
+	c, err := NewZRClient(testConfig())
+	if err != nil {
+		t.Fatalf("NewZRClient: %v", err)
+	}
+
+	next := testConfig()
+	next.UI.Host = "other.example"
+	next.UI.ProtectHardDelete = true
+	next.Timeout = time.Minute
+
+	if err := c.ApplyConfig(next); err != nil {
+		t.Fatalf("ApplyConfig: %v", err)
+	}
+
+	applied := c.Config()
+	if applied.Timeout != time.Minute || !applied.UI.ProtectHardDelete {
+		t.Fatalf("runtime settings not applied: %+v", applied)
+	}
+	if applied.UI.Host != "zr.example" {
+		t.Fatalf("host changed on a running client: %q", applied.UI.Host)
+	}
+}
+
+func TestApplyConfigRejectsInvalid(t *testing.T) {
+	c, err := NewZRClient(testConfig())
+	if err != nil {
+		t.Fatalf("NewZRClient: %v", err)
+	}
+
+	next := testConfig()
+	next.Timeout = 0
+
+	var sdkErr *errors.SDKError
+	if err := c.ApplyConfig(next); !stderrors.As(err, &sdkErr) || sdkErr.Type != errors.ErrorTypeValidation {
+		t.Fatalf("got %v", err)
+	}
+	if c.Config().Timeout != 5*time.Second {
+		t.Fatalf("invalid configuration partially applied: %+v", c.Config())
+	}
+}
+
+func TestApplyConfigConcurrent(t *testing.T) {
+	c, err := NewZRClient(testConfig())
+	if err != nil {
+		t.Fatalf("NewZRClient: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			next := testConfig()
+			next.Timeout = time.Duration(i+1) * time.Second
+			next.UI.Password = "pass-" + string(rune('a'+i))
+			if err := c.ApplyConfig(next); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if timeout := c.Config().Timeout; timeout < time.Second || timeout > 8*time.Second {
+		t.Fatalf("timeout %v", timeout)
+	}
+}
+
+func TestWatchConfigReportsReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zr.yaml")
+	write := func(timeout string) {
+		content := "ui:\n  host: zr.example\n  username: user\n  password: pass\ntimeout: " + timeout + "\n"
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("5s")
+
+	c, err := NewZRClient(testConfig())
+	if err != nil {
+		t.Fatalf("NewZRClient: %v", err)
+	}
+
+	reloads := make(chan error, 4)
+	watcher, err := c.WatchConfig(path, 5*time.Millisecond, func(err error) { reloads <- err })
+	if err != nil {
+		t.Fatalf("WatchConfig: %v", err)
+	}
+	defer watcher.Stop()
+
+	next := func() error {
+		t.Helper()
+		select {
+		case err := <-reloads:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("no reload reported")
+			return nil
+		}
+	}
+
+	write("20s")
+	if err := next(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if c.Config().Timeout != 20*time.Second {
+		t.Fatalf("timeout %v", c.Config().Timeout)
+	}
+
+	write("0s")
+	if err := next(); err == nil {
+		t.Fatal("expected the invalid file to be reported")
+	}
+	if c.Config().Timeout != 20*time.Second {
+		t.Fatalf("invalid file applied, timeout %v", c.Config().Timeout)
+	}
+}
@@ -29,6 +29,13 @@ func (b *Builder) WithUITimeout(timeout time.Duration) *Builder {
 	return b
 }
 
+// WithUIRateLimit limits the requests sent to the ZR web service, in requests per second
+func (b *Builder) WithUIRateLimit(requestsPerSecond float64, burst int) *Builder {
+	b.config.UI.RateLimit = requestsPerSecond
+	b.config.UI.RateBurst = burst
+	return b
+}
+
 // WithDBConfig sets database configuration
 func (b *Builder) WithDBConfig(host string, port int, database, username, password string) *Builder {
 	b.config.DB.Host = host
@@ -39,6 +46,44 @@ func (b *Builder) WithDBConfig(host string, port int, database, username, passwo
 	return b
 }
 
+// WithDBDriver sets the database/sql driver name and, optionally, the SQL dialect
+func (b *Builder) WithDBDriver(driver, dialect string) *Builder {
+	b.config.DB.Driver = driver
+	b.config.DB.Dialect = dialect
+	return b
+}
+
+// WithDBPool sets the database connection pool limits
+func (b *Builder) WithDBPool(maxOpen, maxIdle int, maxLifetime, maxIdleTime time.Duration) *Builder {
+	b.config.DB.MaxOpenConns = maxOpen
+	b.config.DB.MaxIdleConns = maxIdle
+	b.config.DB.ConnMaxLifetime = maxLifetime
+	b.config.DB.ConnMaxIdleTime = maxIdleTime
+	return b
+}
+
+// WithDBTimeouts sets the database statement timeout and health check interval
+func (b *Builder) WithDBTimeouts(statementTimeout, healthCheckInterval time.Duration) *Builder {
+	b.config.DB.StatementTimeout = statementTimeout
+	b.config.DB.HealthCheckInterval = healthCheckInterval
+	return b
+}
+
+// WithDBQueryLogging sets the slow query threshold and the columns whose bound values are masked in logs
+func (b *Builder) WithDBQueryLogging(slowQueryThreshold time.Duration, maskedColumns ...string) *Builder {
+	b.config.DB.SlowQueryThreshold = slowQueryThreshold
+	if len(maskedColumns) > 0 {
+		b.config.DB.MaskedColumns = maskedColumns
+	}
+	return b
+}
+
+// WithDBSchemaCheck sets the behavior on unsupported ZR schema versions: SchemaCheckDegraded, SchemaCheckStrict or SchemaCheckOff
+func (b *Builder) WithDBSchemaCheck(mode string) *Builder {
+	b.config.DB.SchemaCheck = mode
+	return b
+}
+
 // WithTimeout sets global timeout
 func (b *Builder) WithTimeout(timeout time.Duration) *Builder {
 	b.config.Timeout = timeout
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -24,21 +25,54 @@ type UIConfig struct {
 	Host               string // "https://20.0.0.50:8443"
 	BasePath           string // "/CustomerMediaWebService"
 	Username           string // For Basic Auth
-	Password           string // For Basic Auth
+	Password           string // For Basic Auth, plain or a secret reference (env:, file:, cmd:), plain: escapes a literal prefix
 	Timeout            time.Duration
 	InsecureSkipVerify bool
+	ProtectHardDelete  bool // Refuse hard contract deletes unless forced
+
+	// Client-side rate limit of the requests sent to the ZR web service
+	RateLimit float64 // Requests per second, 0 = unlimited
+	RateBurst int     // Requests allowed at once above the rate, 0 = 1
 }
 
 // DBConfig contains database settings
 type DBConfig struct {
+	Driver   string // database/sql driver registered by the application: "oracle" (go-ora), "godror", "postgres", "pgx"; empty = derived from Dialect
+	Dialect  string // SQL dialect: "oracle" or "postgres", derived from Driver when empty; both empty = "oracle"
 	Host     string
 	Port     int
-	Database string
+	Database string // Oracle service name or Postgres database name
 	Username string
-	Password string
-	SSLMode  bool // disable, require, verify-ca, verify-full
+	Password string // Plain or a secret reference (env:, file:, cmd:), plain: escapes a literal prefix
+
+	// Deprecated: use SSLPolicy. true requires SSL ("require") when SSLPolicy is empty.
+	SSLMode   bool
+	SSLPolicy string // Postgres only: disable, require, verify-ca, verify-full; empty = SSLMode
+
+	// Connection pool (the ZR database is shared with the vendor services, the pool is always bounded)
+	MaxOpenConns        int           // 0 = 10
+	MaxIdleConns        int           // 0 = 2, never above MaxOpenConns
+	ConnMaxLifetime     time.Duration // 0 = 30 minutes
+	ConnMaxIdleTime     time.Duration // 0 = 5 minutes
+	StatementTimeout    time.Duration // Per statement, 0 = no timeout besides the caller context
+	HealthCheckInterval time.Duration // Background ping interval, 0 = disabled
+
+	// Query logging
+	SlowQueryThreshold time.Duration // Queries slower than this log at warn, 0 = 1 second
+	MaskedColumns      []string      // Columns whose bound values are masked in logs, nil = default sensitive columns, empty = no masking
+
+	// SchemaCheck selects what happens when the ZR schema version is not supported:
+	// "degraded" (default) keeps reading but refuses writes, "strict" fails the connection, "off" skips the check
+	SchemaCheck string
 }
 
+// Schema check modes (DBConfig.SchemaCheck)
+const (
+	SchemaCheckDegraded = "degraded"
+	SchemaCheckStrict   = "strict"
+	SchemaCheckOff      = "off"
+)
+
 // LoggerConfig defines logging settings
 type LoggerConfig struct {
 	Level       string // debug, info, warn, error
@@ -46,6 +80,28 @@ type LoggerConfig struct {
 	PrettyPrint bool // For development
 }
 
+// DefaultConfig returns the configuration defaults, completed by LoadFile with the file values
+func DefaultConfig() *Config {
+	return &Config{
+		UI: UIConfig{
+			Timeout: 30 * time.Second,
+		},
+		DB: DBConfig{
+			MaxOpenConns:       10,
+			MaxIdleConns:       2,
+			ConnMaxLifetime:    30 * time.Minute,
+			ConnMaxIdleTime:    5 * time.Minute,
+			SlowQueryThreshold: time.Second,
+			SchemaCheck:        SchemaCheckDegraded,
+		},
+		Timeout: 30 * time.Second,
+		Logger: LoggerConfig{
+			Level:   "info",
+			Enabled: true,
+		},
+	}
+}
+
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
 
@@ -53,10 +109,11 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("UI config validation failed: %w", err)
 	}
 
-	/*if err := c.DB.Validate(); err != nil {
-		return fmt.Errorf("DB config validation failed: %w", err)
+	if c.DB.IsConfigured() {
+		if err := c.DB.Validate(); err != nil {
+			return fmt.Errorf("DB config validation failed: %w", err)
+		}
 	}
-	*/
 
 	if c.Timeout <= 0 {
 		return errors.New("timeout must be greater than 0")
@@ -79,9 +136,60 @@ func (u *UIConfig) Validate() error {
 		return errors.New("auth password is required")
 	}
 
+	if err := validateSecretRef(u.Password); err != nil {
+		return fmt.Errorf("auth password: %w", err)
+	}
+
+	if u.Timeout < 0 {
+		return errors.New("timeout cannot be negative")
+	}
+
+	if u.RateLimit < 0 || u.RateBurst < 0 {
+		return errors.New("rate limit cannot be negative")
+	}
+
 	return nil
 }
 
+// IsConfigured reports whether database access is configured
+func (d *DBConfig) IsConfigured() bool {
+	return d.Host != "" || d.Driver != ""
+}
+
+// Postgres SSL policies (DBConfig.SSLPolicy)
+const (
+	SSLPolicyDisable    = "disable"
+	SSLPolicyRequire    = "require"
+	SSLPolicyVerifyCA   = "verify-ca"
+	SSLPolicyVerifyFull = "verify-full"
+)
+
+// DriverName returns the database/sql driver to open: Driver, or the default driver of the dialect
+func (d *DBConfig) DriverName() string {
+	if d.Driver != "" {
+		return d.Driver
+	}
+
+	switch strings.ToLower(d.Dialect) {
+	case "postgres", "postgresql":
+		return "postgres"
+	default:
+		return "oracle"
+	}
+}
+
+// SSL returns the Postgres sslmode: SSLPolicy, or the deprecated SSLMode flag
+func (d *DBConfig) SSL() string {
+	switch {
+	case d.SSLPolicy != "":
+		return d.SSLPolicy
+	case d.SSLMode:
+		return SSLPolicyRequire
+	default:
+		return SSLPolicyDisable
+	}
+}
+
 // Validate checks DB configuration
 func (d *DBConfig) Validate() error {
 	if d.Host == "" {
@@ -101,6 +209,33 @@ func (d *DBConfig) Validate() error {
 	}
 
 	// Password can be empty for some auth methods
+	if err := validateSecretRef(d.Password); err != nil {
+		return fmt.Errorf("database password: %w", err)
+	}
+
+	if d.MaxOpenConns < 0 || d.MaxIdleConns < 0 {
+		return errors.New("database pool sizes cannot be negative")
+	}
+
+	if d.MaxOpenConns > 0 && d.MaxIdleConns > d.MaxOpenConns {
+		return errors.New("database max idle connections cannot exceed max open connections")
+	}
+
+	if d.ConnMaxLifetime < 0 || d.ConnMaxIdleTime < 0 || d.StatementTimeout < 0 || d.HealthCheckInterval < 0 || d.SlowQueryThreshold < 0 {
+		return errors.New("database durations cannot be negative")
+	}
+
+	switch d.SSLPolicy {
+	case "", SSLPolicyDisable, SSLPolicyRequire, SSLPolicyVerifyCA, SSLPolicyVerifyFull:
+	default:
+		return fmt.Errorf("database SSL policy must be %q, %q, %q or %q", SSLPolicyDisable, SSLPolicyRequire, SSLPolicyVerifyCA, SSLPolicyVerifyFull)
+	}
+
+	switch d.SchemaCheck {
+	case "", SchemaCheckDegraded, SchemaCheckStrict, SchemaCheckOff:
+	default:
+		return fmt.Errorf("database schema check must be %q, %q or %q", SchemaCheckDegraded, SchemaCheckStrict, SchemaCheckOff)
+	}
 
 	return nil
 }
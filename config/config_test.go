@@ -0,0 +1,75 @@
+package config
+
+import "testing"
+
+func validDBConfig() DBConfig {
+	return DBConfig{Host: "zr", Port: 1521, Database: "ZR", Username: "zr", Password: "secret"}
+}
+
+func TestDBConfigDriverName(t *testing.T) {
+	tests := []struct {
+		driver, dialect, want string
+	}{
+		{"", "", "oracle"},
+		{"", "oracle", "oracle"},
+		{"", "postgres", "postgres"},
+		{"", "PostgreSQL", "postgres"},
+		{"pgx", "postgres", "pgx"},
+		{"godror", "", "godror"},
+	}
+
+	for _, tt := range tests {
+		cfg := DBConfig{Driver: tt.driver, Dialect: tt.dialect}
+		if got := cfg.DriverName(); got != tt.want {
+			t.Errorf("DriverName(%q, %q) = %q, want %q", tt.driver, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestDBConfigSSL(t *testing.T) {
+	tests := []struct {
+		sslMode bool
+		policy  string
+		want    string
+	}{
+		{false, "", SSLPolicyDisable},
+		{true, "", SSLPolicyRequire},
+		{false, SSLPolicyVerifyCA, SSLPolicyVerifyCA},
+		{true, SSLPolicyDisable, SSLPolicyDisable},
+	}
+
+	for _, tt := range tests {
+		cfg := DBConfig{SSLMode: tt.sslMode, SSLPolicy: tt.policy}
+		if got := cfg.SSL(); got != tt.want {
+			t.Errorf("SSL(%v, %q) = %q, want %q", tt.sslMode, tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestDBConfigValidateWithoutDriver(t *testing.T) {
+	cfg := validDBConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	cfg.SSLPolicy = "prefer"
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected an error for an unknown SSL policy")
+	}
+}
+
+func TestApplyEnvSSL(t *testing.T) {
+	env := map[string]string{"ZR_DB_SSL_MODE": "true", "ZR_DB_SSL_POLICY": SSLPolicyVerifyFull}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	cfg := &Config{}
+	if err := cfg.applyEnv("ZR", lookup); err != nil {
+		t.Fatalf("applyEnv: %v", err)
+	}
+	if !cfg.DB.SSLMode || cfg.DB.SSLPolicy != SSLPolicyVerifyFull {
+		t.Fatalf("got SSLMode %v, SSLPolicy %q", cfg.DB.SSLMode, cfg.DB.SSLPolicy)
+	}
+}
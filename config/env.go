@@ -0,0 +1,75 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// DefaultEnvPrefix is used by FromEnv when no prefix is given
+const DefaultEnvPrefix = "ZR"
+
+// EnvError reports an environment variable that could not be parsed
+type EnvError struct {
+	Variable string
+	Err      error
+}
+
+func (e *EnvError) Error() string {
+	return fmt.Sprintf("invalid environment variable %s: %v", e.Variable, e.Err)
+}
+
+func (e *EnvError) Unwrap() error {
+	return e.Err
+}
+
+// FromEnv reads the configuration from environment variables named after the prefix and
+// the setting, e.g. ZR_UI_HOST, ZR_UI_TIMEOUT, ZR_DB_MAX_OPEN_CONNS, ZR_TIMEOUT, ZR_LOGGER_LEVEL.
+// Durations accept Go durations ("30s") or seconds ("30"), booleans accept true/false, 1/0, yes/no, on/off.
+// Variables are read over DefaultConfig: unset and empty variables keep their default.
+//
+// The result is not validated, so missing values can still be set in code, see NewBuilderFromEnv.
+// Every invalid variable is reported, each as an *EnvError.
+func FromEnv(prefix string) (*Config, error) {
+	cfg := DefaultConfig()
+	if err := cfg.applyEnv(prefix, os.LookupEnv); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// NewBuilderFromEnv creates a builder initialized from the environment (see FromEnv).
+// Values set through the builder override the environment.
+func NewBuilderFromEnv(prefix string) (*Builder, error) {
+	cfg, err := FromEnv(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &Builder{config: cfg}, nil
+}
+
+// applyEnv sets every field whose variable is present
+func (c *Config) applyEnv(prefix string, lookup func(string) (string, bool)) error {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+
+	var errs []error
+	for _, f := range fields {
+		name := envName(prefix, f.key)
+
+		value, ok := lookup(name)
+		if !ok || value == "" {
+			continue
+		}
+
+		if err := f.set(c, value); err != nil {
+			if f.secret {
+				err = errors.New("invalid value")
+			}
+			errs = append(errs, &EnvError{Variable: name, Err: err})
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFromEnvDefaults(t *testing.T) {
+	cfg, err := FromEnv("ZRTEST")
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, DefaultConfig()) {
+		t.Fatalf("got %+v, want the defaults %+v", cfg, DefaultConfig())
+	}
+}
+
+func TestFromEnvOverridesDefaults(t *testing.T) {
+	t.Setenv("ZRTEST_UI_HOST", "https://zr.example:8443")
+	t.Setenv("ZRTEST_UI_TIMEOUT", "10")
+	t.Setenv("ZRTEST_DB_MAX_OPEN_CONNS", "4")
+	t.Setenv("ZRTEST_LOGGER_ENABLED", "off")
+	t.Setenv("ZRTEST_TIMEOUT", "")
+
+	cfg, err := FromEnv("ZRTEST")
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+
+	if cfg.UI.Host != "https://zr.example:8443" || cfg.UI.Timeout != 10*time.Second || cfg.DB.MaxOpenConns != 4 || cfg.Logger.Enabled {
+		t.Fatalf("variables not applied: %+v", cfg)
+	}
+
+	// Settings without a variable, or with an empty one, keep their default
+	defaults := DefaultConfig()
+	if cfg.Timeout != defaults.Timeout || cfg.DB.MaxIdleConns != defaults.DB.MaxIdleConns ||
+		cfg.DB.SchemaCheck != defaults.DB.SchemaCheck || cfg.Logger.Level != defaults.Logger.Level {
+		t.Fatalf("defaults lost: %+v", cfg)
+	}
+}
+
+func TestFromEnvReportsEveryInvalidVariable(t *testing.T) {
+	t.Setenv("ZRTEST_UI_TIMEOUT", "soon")
+	t.Setenv("ZRTEST_DB_PORT", "x")
+	t.Setenv("ZRTEST_DB_PASSWORD", "ignored")
+
+	_, err := FromEnv("ZRTEST")
+
+	var envErr *EnvError
+	if !errors.As(err, &envErr) {
+		t.Fatalf("got %v", err)
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); !ok || len(joined.Unwrap()) != 2 {
+		t.Fatalf("expected two errors, got %v", err)
+	}
+}
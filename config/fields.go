@@ -0,0 +1,154 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field is a configuration setting that can be read from the environment or a file.
+// Its environment variable is the prefix followed by the key in upper case, dots replaced
+// by underscores: "ui.host" is ZR_UI_HOST.
+type field struct {
+	key    string
+	secret bool // Value never shown in errors
+	set    func(c *Config, value string) error
+}
+
+// fields lists every setting of Config
+var fields = []field{
+	{key: "ui.host", set: setString(func(c *Config) *string { return &c.UI.Host })},
+	{key: "ui.base_path", set: setString(func(c *Config) *string { return &c.UI.BasePath })},
+	{key: "ui.username", set: setString(func(c *Config) *string { return &c.UI.Username })},
+	{key: "ui.password", secret: true, set: setString(func(c *Config) *string { return &c.UI.Password })},
+	{key: "ui.timeout", set: setDuration(func(c *Config) *time.Duration { return &c.UI.Timeout })},
+	{key: "ui.insecure_skip_verify", set: setBool(func(c *Config) *bool { return &c.UI.InsecureSkipVerify })},
+	{key: "ui.protect_hard_delete", set: setBool(func(c *Config) *bool { return &c.UI.ProtectHardDelete })},
+	{key: "ui.rate_limit", set: setFloat(func(c *Config) *float64 { return &c.UI.RateLimit })},
+	{key: "ui.rate_burst", set: setInt(func(c *Config) *int { return &c.UI.RateBurst })},
+
+	{key: "db.driver", set: setString(func(c *Config) *string { return &c.DB.Driver })},
+	{key: "db.dialect", set: setString(func(c *Config) *string { return &c.DB.Dialect })},
+	{key: "db.host", set: setString(func(c *Config) *string { return &c.DB.Host })},
+	{key: "db.port", set: setInt(func(c *Config) *int { return &c.DB.Port })},
+	{key: "db.database", set: setString(func(c *Config) *string { return &c.DB.Database })},
+	{key: "db.username", set: setString(func(c *Config) *string { return &c.DB.Username })},
+	{key: "db.password", secret: true, set: setString(func(c *Config) *string { return &c.DB.Password })},
+	{key: "db.ssl_mode", set: setBool(func(c *Config) *bool { return &c.DB.SSLMode })},
+	{key: "db.ssl_policy", set: setString(func(c *Config) *string { return &c.DB.SSLPolicy })},
+	{key: "db.max_open_conns", set: setInt(func(c *Config) *int { return &c.DB.MaxOpenConns })},
+	{key: "db.max_idle_conns", set: setInt(func(c *Config) *int { return &c.DB.MaxIdleConns })},
+	{key: "db.conn_max_lifetime", set: setDuration(func(c *Config) *time.Duration { return &c.DB.ConnMaxLifetime })},
+	{key: "db.conn_max_idle_time", set: setDuration(func(c *Config) *time.Duration { return &c.DB.ConnMaxIdleTime })},
+	{key: "db.statement_timeout", set: setDuration(func(c *Config) *time.Duration { return &c.DB.StatementTimeout })},
+	{key: "db.health_check_interval", set: setDuration(func(c *Config) *time.Duration { return &c.DB.HealthCheckInterval })},
+	{key: "db.slow_query_threshold", set: setDuration(func(c *Config) *time.Duration { return &c.DB.SlowQueryThreshold })},
+	{key: "db.masked_columns", set: setList(func(c *Config) *[]string { return &c.DB.MaskedColumns })},
+	{key: "db.schema_check", set: setString(func(c *Config) *string { return &c.DB.SchemaCheck })},
+
+	{key: "timeout", set: setDuration(func(c *Config) *time.Duration { return &c.Timeout })},
+
+	{key: "logger.level", set: setString(func(c *Config) *string { return &c.Logger.Level })},
+	{key: "logger.enabled", set: setBool(func(c *Config) *bool { return &c.Logger.Enabled })},
+	{key: "logger.pretty_print", set: setBool(func(c *Config) *bool { return &c.Logger.PrettyPrint })},
+}
+
+// envName returns the environment variable of a field key
+func envName(prefix, key string) string {
+	name := strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+	if prefix == "" {
+		return name
+	}
+	return strings.TrimSuffix(prefix, "_") + "_" + name
+}
+
+func setString(target func(*Config) *string) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		*target(c) = value
+		return nil
+	}
+}
+
+func setInt(target func(*Config) *int) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+		*target(c) = n
+		return nil
+	}
+}
+
+func setFloat(target func(*Config) *float64) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		*target(c) = f
+		return nil
+	}
+}
+
+func setBool(target func(*Config) *bool) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		b, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		*target(c) = b
+		return nil
+	}
+}
+
+func setDuration(target func(*Config) *time.Duration) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		d, err := parseDuration(value)
+		if err != nil {
+			return err
+		}
+		*target(c) = d
+		return nil
+	}
+}
+
+func setList(target func(*Config) *[]string) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		items := []string{} // Empty rather than nil, an explicit empty list is not the default
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*target(c) = items
+		return nil
+	}
+}
+
+// parseBool accepts the strconv.ParseBool forms plus yes/no and on/off
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "t", "true", "yes", "y", "on":
+		return true, nil
+	case "0", "f", "false", "no", "n", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is not a boolean", value)
+}
+
+// parseDuration accepts Go durations ("30s", "1m30s") and plain numbers of seconds
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a duration", value)
+	}
+	return d, nil
+}
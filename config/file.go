@@ -0,0 +1,203 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LoadFile reads a JSON or YAML configuration file over DefaultConfig and validates the result.
+// The format follows the extension (.json, .yaml, .yml), other files are sniffed.
+//
+// Keys mirror the environment variables of FromEnv, nested by section:
+//
+//	ui:
+//	  host: https://20.0.0.50:8443
+//	  timeout: 30s
+//	logger:
+//	  level: debug
+//
+// Keys are matched ignoring case, "_" and "-" (rate_limit, rateLimit). Unknown keys are errors,
+// so a misspelled setting never silently keeps its default.
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	cfg, err := parseFile(path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// parseFile parses the content of a configuration file over DefaultConfig, without validating it
+func parseFile(path string, data []byte) (*Config, error) {
+	var (
+		entries []fileEntry
+		err     error
+	)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		entries, err = parseJSON(data)
+	case ".yaml", ".yml":
+		entries, err = parseYAML(data)
+	default:
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+			entries, err = parseJSON(data)
+		} else {
+			entries, err = parseYAML(data)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	cfg := DefaultConfig()
+	if err := cfg.applyEntries(entries); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// applyEntries sets the fields named by entries, reporting every unknown key and invalid value
+func (c *Config) applyEntries(entries []fileEntry) error {
+	known := make(map[string]field, len(fields))
+	sections := make(map[string]bool)
+	for _, f := range fields {
+		known[normalizeKey(f.key)] = f
+		for key := f.key; strings.Contains(key, "."); {
+			key = key[:strings.LastIndex(key, ".")]
+			sections[normalizeKey(key)] = true
+		}
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		where := ""
+		if entry.line > 0 {
+			where = fmt.Sprintf("line %d: ", entry.line)
+		}
+
+		f, ok := known[normalizeKey(entry.key)]
+		if !ok {
+			// An empty section ("ui:" alone) sets nothing
+			if entry.value == "" && !entry.list && sections[normalizeKey(entry.key)] {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("%sunknown key %q", where, entry.key))
+			continue
+		}
+
+		// An empty value keeps the default, an empty list ("[]") is applied
+		if entry.value == "" && !entry.list {
+			continue
+		}
+
+		if err := f.set(c, entry.value); err != nil {
+			if f.secret {
+				err = errors.New("invalid value")
+			}
+			errs = append(errs, fmt.Errorf("%s%s: %w", where, entry.key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// normalizeKey drops case, "_" and "-" from a dotted key
+func normalizeKey(key string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+}
+
+// parseJSON flattens a JSON object into dotted keys, arrays of scalars become comma separated values
+func parseJSON(data []byte) ([]fileEntry, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var root map[string]any
+	if err := dec.Decode(&root); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var entries []fileEntry
+	if err := flattenJSON("", root, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func flattenJSON(prefix string, object map[string]any, entries *[]fileEntry) error {
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := object[name]
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			if len(v) == 0 {
+				// Reported like an empty YAML key when unknown
+				*entries = append(*entries, fileEntry{key: key})
+				continue
+			}
+			if err := flattenJSON(key, v, entries); err != nil {
+				return err
+			}
+		case []any:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				s, ok := jsonScalar(item)
+				if !ok {
+					return fmt.Errorf("%s: only lists of values are supported", key)
+				}
+				items = append(items, s)
+			}
+			*entries = append(*entries, fileEntry{key: key, value: strings.Join(items, ","), list: true})
+		default:
+			s, ok := jsonScalar(v)
+			if !ok {
+				return fmt.Errorf("%s: unsupported value", key)
+			}
+			*entries = append(*entries, fileEntry{key: key, value: s})
+		}
+	}
+	return nil
+}
+
+// jsonScalar formats a JSON string, number, boolean or null
+func jsonScalar(value any) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		if v {
+			return "true", true
+		}
+		return "false", true
+	}
+	return "", false
+}
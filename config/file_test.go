@@ -0,0 +1,45 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFileReportsEmptyUnknownKeys(t *testing.T) {
+	for path, data := range map[string]string{
+		"zr.yaml": "ui:\n  host: https://zr.example:8443\ncolour:\nlogger:\n",
+		"zr.json": `{"ui": {"host": "https://zr.example:8443"}, "colour": {}, "logger": {}}`,
+	} {
+		_, err := parseFile(path, []byte(data))
+		if err == nil || !strings.Contains(err.Error(), `unknown key "colour"`) {
+			t.Errorf("%s: got %v", path, err)
+		}
+		if err != nil && strings.Contains(err.Error(), `"logger"`) {
+			t.Errorf("%s: empty section reported: %v", path, err)
+		}
+	}
+}
+
+func TestParseFileEmptyListDisablesMasking(t *testing.T) {
+	for path, data := range map[string]string{
+		"zr.yaml": "db:\n  masked_columns: []\n",
+		"zr.json": `{"db": {"masked_columns": []}}`,
+	} {
+		cfg, err := parseFile(path, []byte(data))
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if cfg.DB.MaskedColumns == nil || len(cfg.DB.MaskedColumns) != 0 {
+			t.Errorf("%s: masked columns %#v, want an empty list", path, cfg.DB.MaskedColumns)
+		}
+	}
+
+	// Without a value, the setting keeps its default
+	cfg, err := parseFile("zr.yaml", []byte("db:\n  masked_columns:\n  host: zr\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DB.MaskedColumns != nil {
+		t.Fatalf("masked columns %#v, want the default", cfg.DB.MaskedColumns)
+	}
+}
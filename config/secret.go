@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Secret reference prefixes accepted by UIConfig.Password and DBConfig.Password
+const (
+	SecretEnvPrefix  = "env:"  // env:ZR_PASS reads an environment variable
+	SecretFilePrefix = "file:" // file:/run/secrets/zr reads a file
+	SecretCmdPrefix  = "cmd:"  // cmd:vault kv get -field=password zr runs a command and reads its output
+
+	// SecretPlainPrefix escapes a literal password that starts with a reference prefix:
+	// plain:env:x is the password "env:x"
+	SecretPlainPrefix = "plain:"
+)
+
+// secretCmdTimeout bounds the commands run to resolve cmd: references
+const secretCmdTimeout = 10 * time.Second
+
+// SecretError reports a secret reference that could not be resolved.
+// It never carries the secret value nor the arguments of a command.
+type SecretError struct {
+	Ref string // Reference without command arguments, e.g. "env:ZR_PASS", "cmd:vault"
+	Err error
+}
+
+func (e *SecretError) Error() string {
+	return fmt.Sprintf("failed to resolve secret %s: %v", e.Ref, e.Err)
+}
+
+func (e *SecretError) Unwrap() error {
+	return e.Err
+}
+
+// IsSecretRef reports whether value is a secret reference rather than a plain value.
+// A value escaped with plain: is a plain value, see PlainSecret.
+func IsSecretRef(value string) bool {
+	return strings.HasPrefix(value, SecretEnvPrefix) ||
+		strings.HasPrefix(value, SecretFilePrefix) ||
+		strings.HasPrefix(value, SecretCmdPrefix)
+}
+
+// PlainSecret returns a value that is not a secret reference, without its plain: escape
+func PlainSecret(value string) string {
+	return strings.TrimPrefix(value, SecretPlainPrefix)
+}
+
+// ResolveSecret returns the value a secret reference points to, plain values are returned
+// unchanged (see PlainSecret). The trailing line break of files and command outputs is dropped.
+//
+// Commands are run without a shell and must finish within 10 seconds. The command line is split
+// with strings.Fields: arguments cannot be quoted and never contain spaces, so a command needing
+// them must be wrapped in a script.
+func ResolveSecret(ctx context.Context, value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, SecretEnvPrefix):
+		name := strings.TrimPrefix(value, SecretEnvPrefix)
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", &SecretError{Ref: value, Err: errors.New("environment variable is not set")}
+		}
+		return secret, nil
+
+	case strings.HasPrefix(value, SecretFilePrefix):
+		data, err := os.ReadFile(strings.TrimPrefix(value, SecretFilePrefix))
+		if err != nil {
+			return "", &SecretError{Ref: value, Err: err}
+		}
+		return trimLineBreak(string(data)), nil
+
+	case strings.HasPrefix(value, SecretCmdPrefix):
+		return runSecretCmd(ctx, strings.Fields(strings.TrimPrefix(value, SecretCmdPrefix)))
+	}
+
+	return PlainSecret(value), nil
+}
+
+// runSecretCmd runs a cmd: reference, its output and arguments are never part of errors
+func runSecretCmd(ctx context.Context, args []string) (string, error) {
+	if len(args) == 0 {
+		return "", &SecretError{Ref: SecretCmdPrefix, Err: errors.New("empty command")}
+	}
+	ref := SecretCmdPrefix + args[0]
+
+	ctx, cancel := context.WithTimeout(ctx, secretCmdTimeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		switch {
+		case ctx.Err() != nil:
+			err = ctx.Err()
+		case errors.As(err, &exitErr):
+			err = fmt.Errorf("command exited with status %d", exitErr.ExitCode())
+		}
+		return "", &SecretError{Ref: ref, Err: err}
+	}
+
+	return trimLineBreak(stdout.String()), nil
+}
+
+// validateSecretRef checks that a secret reference names its source
+func validateSecretRef(value string) error {
+	for _, prefix := range []string{SecretEnvPrefix, SecretFilePrefix, SecretCmdPrefix} {
+		if strings.HasPrefix(value, prefix) && strings.TrimSpace(strings.TrimPrefix(value, prefix)) == "" {
+			return fmt.Errorf("secret reference %q is incomplete", prefix)
+		}
+	}
+	return nil
+}
+
+func trimLineBreak(s string) string {
+	s = strings.TrimSuffix(s, "\n")
+	return strings.TrimSuffix(s, "\r")
+}
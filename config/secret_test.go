@@ -0,0 +1,78 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveSecret(t *testing.T) {
+	t.Setenv("ZRTEST_SECRET", "from-env")
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "secret")
+	if err := os.WriteFile(file, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"literal", "literal"},
+		{"env:ZRTEST_SECRET", "from-env"},
+		{"file:" + file, "from-file"},
+		{"cmd:echo from-cmd", "from-cmd"},
+
+		// plain: escapes literal passwords starting with a reference prefix
+		{"plain:env:ZRTEST_SECRET", "env:ZRTEST_SECRET"},
+		{"plain:cmd:rm -rf /", "cmd:rm -rf /"},
+		{"plain:plain:x", "plain:x"},
+	}
+
+	for _, tt := range tests {
+		got, err := ResolveSecret(context.Background(), tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("ResolveSecret(%q) = %q, %v; want %q", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestResolveSecretPlainIsNotAReference(t *testing.T) {
+	if IsSecretRef("plain:env:X") {
+		t.Fatal("an escaped value is not a reference")
+	}
+	if got := PlainSecret("plain:file:/x"); got != "file:/x" {
+		t.Fatalf("PlainSecret = %q", got)
+	}
+}
+
+func TestResolveSecretCmdSplitsOnSpaces(t *testing.T) {
+	// Arguments cannot be quoted: the quotes are passed to the command
+	got, err := ResolveSecret(context.Background(), `cmd:echo "a b"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != `"a b"` {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestResolveSecretErrorsHideValues(t *testing.T) {
+	_, err := ResolveSecret(context.Background(), "cmd:sh -c exit-with-secret-arg")
+
+	var secretErr *SecretError
+	if !errors.As(err, &secretErr) || secretErr.Ref != "cmd:sh" {
+		t.Fatalf("got %v", err)
+	}
+	if strings.Contains(err.Error(), "secret-arg") {
+		t.Fatalf("command arguments leaked in %q", err)
+	}
+
+	if _, err := ResolveSecret(context.Background(), "env:ZRTEST_UNSET_SECRET"); !errors.As(err, &secretErr) {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultWatchInterval is the poll interval of a Watcher created with a zero interval
+const DefaultWatchInterval = 5 * time.Second
+
+// Watcher polls a configuration file and reloads it when its content changes.
+// It polls instead of relying on file system notifications, so it works on every platform and
+// with files replaced by a rename (Kubernetes ConfigMaps, editors writing a temporary file).
+type Watcher struct {
+	path     string
+	interval time.Duration
+	onChange func(*Config, error)
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// Watch starts polling path every interval. Each time the content changes the file is loaded
+// with LoadFile and onChange receives the validated configuration, or the load error; a file that
+// fails to load is reported once per change, and the previous configuration stays in use.
+func Watch(path string, interval time.Duration, onChange func(*Config, error)) (*Watcher, error) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	// Current content is the baseline, only later changes are reported
+	current, err := fileDigest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		path:     path,
+		interval: interval,
+		onChange: onChange,
+		stop:     make(chan struct{}),
+	}
+
+	w.wg.Add(1)
+	go w.run(current)
+
+	return w, nil
+}
+
+func (w *Watcher) run(last []byte) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+
+		digest, err := fileDigest(w.path)
+		if err != nil {
+			// Missing while being replaced, retried on the next tick
+			continue
+		}
+		if bytes.Equal(digest, last) {
+			continue
+		}
+		last = digest
+
+		w.onChange(LoadFile(w.path))
+	}
+}
+
+// Stop stops polling and waits for a running reload to finish
+func (w *Watcher) Stop() {
+	w.once.Do(func() {
+		close(w.stop)
+	})
+	w.wg.Wait()
+}
+
+// fileDigest returns the SHA-256 of a file content
+func fileDigest(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
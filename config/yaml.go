@@ -0,0 +1,222 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// fileEntry is a setting read from a configuration file, keyed by its dotted path
+type fileEntry struct {
+	key   string
+	value string
+	list  bool // Explicit list, applied even when empty
+	line  int  // 0 when unknown (JSON)
+}
+
+// parseYAML reads the YAML subset used by configuration files: nested mappings by indentation,
+// plain and quoted scalars, block ("- item") and flow ("[a, b]") lists of scalars, and comments.
+// Anchors, tags, block scalars and flow mappings are rejected. A key without a value nor
+// children is returned as an empty entry, so that an unknown one is still reported.
+func parseYAML(data []byte) ([]fileEntry, error) {
+	type parent struct {
+		indent int
+		key    string
+	}
+
+	var (
+		entries []fileEntry
+		stack   []parent
+		lists   = make(map[string]int) // List key -> index in entries
+		empty   []fileEntry            // Keys without a value, until a child or list item shows up
+	)
+
+	// filled drops key from the empty keys once it turned out to be a section or a list
+	filled := func(key string) {
+		for i, e := range empty {
+			if e.key == key {
+				empty = append(empty[:i], empty[i+1:]...)
+				return
+			}
+		}
+	}
+
+	lines := strings.Split(strings.TrimPrefix(string(data), "\ufeff"), "\n")
+	for i, raw := range lines {
+		lineNo := i + 1
+		line := strings.TrimRight(stripYAMLComment(strings.TrimRight(raw, "\r")), " \t")
+
+		text := strings.TrimLeft(line, " ")
+		if text == "" {
+			continue
+		}
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", lineNo)
+		}
+
+		indent := len(line) - len(text)
+		if indent == 0 && (text == "---" || text == "...") {
+			continue
+		}
+
+		// List item of the enclosing key
+		if text == "-" || strings.HasPrefix(text, "- ") {
+			for len(stack) > 0 && stack[len(stack)-1].indent > indent {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: list item without a key", lineNo)
+			}
+
+			value, err := parseYAMLScalar(strings.TrimSpace(strings.TrimPrefix(text, "-")))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+
+			key := stack[len(stack)-1].key
+			if index, ok := lists[key]; ok {
+				entries[index].value += "," + value
+			} else {
+				filled(key)
+				lists[key] = len(entries)
+				entries = append(entries, fileEntry{key: key, value: value, list: true, line: lineNo})
+			}
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		name, rest, ok := cutYAMLKey(text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", lineNo)
+		}
+
+		key := name
+		if len(stack) > 0 {
+			key = stack[len(stack)-1].key + "." + name
+			filled(stack[len(stack)-1].key)
+		}
+
+		if rest == "" {
+			// Section, or list, on the following lines
+			stack = append(stack, parent{indent: indent, key: key})
+			empty = append(empty, fileEntry{key: key, line: lineNo})
+			continue
+		}
+
+		value, err := parseYAMLValue(rest)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		entries = append(entries, fileEntry{key: key, value: value, list: strings.HasPrefix(rest, "["), line: lineNo})
+	}
+
+	return append(entries, empty...), nil
+}
+
+// cutYAMLKey splits "key: value", the key may be quoted
+func cutYAMLKey(text string) (key, rest string, ok bool) {
+	if text[0] == '"' || text[0] == '\'' {
+		end := strings.IndexByte(text[1:], text[0])
+		if end < 0 || !strings.HasPrefix(text[end+2:], ":") {
+			return "", "", false
+		}
+		key, rest = text[1:end+1], text[end+3:]
+	} else {
+		index := strings.Index(text, ": ")
+		switch {
+		case index >= 0:
+			key, rest = text[:index], text[index+2:]
+		case strings.HasSuffix(text, ":"):
+			key = text[:len(text)-1]
+		default:
+			return "", "", false
+		}
+	}
+
+	key = strings.TrimSpace(key)
+	return key, strings.TrimSpace(rest), key != ""
+}
+
+// parseYAMLValue parses a scalar or a flow list, lists are returned comma separated
+func parseYAMLValue(value string) (string, error) {
+	if !strings.HasPrefix(value, "[") {
+		return parseYAMLScalar(value)
+	}
+
+	if !strings.HasSuffix(value, "]") {
+		return "", fmt.Errorf("unterminated list %s", value)
+	}
+
+	inner := strings.TrimSpace(value[1 : len(value)-1])
+	if inner == "" {
+		return "", nil
+	}
+
+	var items []string
+	for _, item := range strings.Split(inner, ",") {
+		parsed, err := parseYAMLScalar(strings.TrimSpace(item))
+		if err != nil {
+			return "", err
+		}
+		items = append(items, parsed)
+	}
+	return strings.Join(items, ","), nil
+}
+
+// parseYAMLScalar parses a plain, single or double quoted scalar
+func parseYAMLScalar(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	switch value[0] {
+	case '"':
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid double quoted string")
+		}
+		return unquoted, nil
+	case '\'':
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return "", fmt.Errorf("invalid single quoted string")
+		}
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'"), nil
+	case '|', '>':
+		return "", fmt.Errorf("block scalars are not supported")
+	case '&', '*', '!':
+		return "", fmt.Errorf("anchors, aliases and tags are not supported")
+	case '{':
+		return "", fmt.Errorf("flow mappings are not supported")
+	case '[':
+		return "", fmt.Errorf("nested lists are not supported")
+	}
+
+	if value == "~" || value == "null" {
+		return "", nil
+	}
+	return value, nil
+}
+
+// stripYAMLComment removes a trailing comment, "#" starting a line or following a space, outside quotes
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				i++
+			}
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" \t[,", line[i-1]) >= 0):
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
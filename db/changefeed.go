@@ -0,0 +1,263 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// Change feed defaults
+const (
+	defaultChangeInterval  = 30 * time.Second
+	defaultChangeBatchSize = 500
+)
+
+// ChangeFeedOptions configures a change feed
+type ChangeFeedOptions struct {
+	Name      string         // Watermark key, defaults to the entity name ("contract", "participant")
+	Store     WatermarkStore // Persists the watermark, nil = in memory only
+	Interval  time.Duration  // Poll interval of Run and Events, 0 = 30 seconds
+	BatchSize int            // Rows read per query and handler call, 0 = 500
+
+	// Overlap re-reads changes this far before the watermark on every poll, covering
+	// transactions committed after rows with a later timestamp were read. Re-read rows
+	// are delivered again.
+	Overlap time.Duration
+
+	// Start is the initial position when no watermark is stored. Zero delivers every row once,
+	// as created or deleted events.
+	Start time.Time
+}
+
+// ChangeFeed polls a ZR table for rows modified after a persisted watermark.
+//
+// Delivery is at least once: the watermark only moves after the handler accepted a batch,
+// so a failed handler or a crash delivers the batch again. Hard deleted rows are not reported,
+// ZR archives (soft deletes) them, which is reported as a deleted event.
+type ChangeFeed struct {
+	db   *DB
+	src  changeSource
+	opts ChangeFeedOptions
+}
+
+// ChangeHandler processes a batch of change events. Returning an error stops the poll
+// without moving the watermark.
+type ChangeHandler func(ctx context.Context, events []models.ChangeEvent) error
+
+// Changes returns a feed of contract changes
+func (r *ContractRepository) Changes(opts ChangeFeedOptions) *ChangeFeed {
+	return newChangeFeed(r.db, contractChanges, opts)
+}
+
+// Changes returns a feed of participant changes
+func (r *ParticipantRepository) Changes(opts ChangeFeedOptions) *ChangeFeed {
+	return newChangeFeed(r.db, participantChanges, opts)
+}
+
+func newChangeFeed(db *DB, src changeSource, opts ChangeFeedOptions) *ChangeFeed {
+	if opts.Name == "" {
+		opts.Name = src.entity
+	}
+	if opts.Store == nil {
+		opts.Store = NewMemoryWatermarkStore()
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultChangeInterval
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultChangeBatchSize
+	}
+
+	return &ChangeFeed{db: db, src: src, opts: opts}
+}
+
+// Watermark returns the stored position of the feed
+func (f *ChangeFeed) Watermark(ctx context.Context) (Watermark, error) {
+	return f.opts.Store.Load(ctx, f.opts.Name)
+}
+
+// Poll delivers every change after the watermark to handler, batch by batch, and returns
+// the number of events delivered. The watermark is saved after each accepted batch.
+//
+// Rows without a modification time (NULL LASTMODIFIED) cannot be ordered by it: they are read
+// by ID before the modified rows, delivered once with a zero ModifiedAt whatever Start is, then
+// again once updated. Only soft deletes (the archive flag) are emitted as deleted events:
+// a hard deleted row disappears from the table and is never reported.
+func (f *ChangeFeed) Poll(ctx context.Context, handler ChangeHandler) (int, error) {
+	if _, err := f.db.Conn(ctx); err != nil {
+		return 0, err
+	}
+	if caps := f.db.Capabilities(); !caps.ChangeFeeds {
+		return 0, errors.NewDatabaseError("change feeds need ZR schema version "+changeFeedSchemaVersion.String()+" or later, detected "+caps.SchemaVersion.String(), "", "SELECT", nil)
+	}
+
+	mark, err := f.opts.Store.Load(ctx, f.opts.Name)
+	if err != nil {
+		f.db.log().Error("failed to load change feed watermark", logger.String("feed", f.opts.Name), logger.Error(err))
+		return 0, err
+	}
+	if mark.IsZero() && !f.opts.Start.IsZero() {
+		mark = Watermark{Time: f.opts.Start}
+	}
+
+	// Rows created after the previous poll are reported as created
+	createdAfter := mark.Time
+
+	delivered := 0
+	deliver := func(events []models.ChangeEvent, next Watermark) error {
+		if err := handler(ctx, events); err != nil {
+			f.db.log().Warn("change handler failed, batch will be delivered again",
+				logger.String("feed", f.opts.Name),
+				logger.Int("events", len(events)),
+				logger.Error(err),
+			)
+			return err
+		}
+		delivered += len(events)
+
+		if next != mark {
+			if err := f.opts.Store.Save(ctx, f.opts.Name, next); err != nil {
+				f.db.log().Error("failed to save change feed watermark", logger.String("feed", f.opts.Name), logger.Error(err))
+				return err
+			}
+			mark = next
+		}
+		return nil
+	}
+
+	for {
+		q := f.src.unmodifiedQuery(f.db.dialect, mark.UnmodifiedID, f.opts.BatchSize)
+		events, last, err := f.readBatch(ctx, q, createdAfter)
+		if err != nil {
+			return delivered, err
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		next := mark
+		next.UnmodifiedID = last.ID
+		if err := deliver(events, next); err != nil {
+			return delivered, err
+		}
+
+		if len(events) < f.opts.BatchSize {
+			break
+		}
+	}
+
+	since := mark
+	if f.opts.Overlap > 0 && !mark.Time.IsZero() {
+		since = Watermark{Time: mark.Time.Add(-f.opts.Overlap)}
+	}
+
+	for {
+		q := f.src.changeQuery(f.db.dialect, since.Time, since.ID, f.opts.BatchSize)
+		events, last, err := f.readBatch(ctx, q, createdAfter)
+		if err != nil {
+			return delivered, err
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		next := mark
+		if last.after(mark) {
+			next.Time, next.ID = last.Time, last.ID
+		}
+		if err := deliver(events, next); err != nil {
+			return delivered, err
+		}
+		since = last
+
+		if len(events) < f.opts.BatchSize {
+			break
+		}
+	}
+
+	if delivered > 0 {
+		f.db.log().Info("changes delivered", logger.String("feed", f.opts.Name), logger.Int("Count", delivered))
+	}
+
+	return delivered, nil
+}
+
+// readBatch reads a batch of changes with q, returning the position of its last row
+func (f *ChangeFeed) readBatch(ctx context.Context, q *selectQuery, createdAfter time.Time) ([]models.ChangeEvent, Watermark, error) {
+	conn, err := f.db.querier(ctx)
+	if err != nil {
+		return nil, Watermark{}, err
+	}
+
+	rows, err := collect(streamRows(ctx, f.db, conn, q.String(), q.Args(), f.src.scan))
+	if err != nil {
+		f.db.log().Error("failed to read changes", logger.String("feed", f.opts.Name), logger.Error(err))
+		return nil, Watermark{}, err
+	}
+
+	events := make([]models.ChangeEvent, 0, len(rows))
+	var last Watermark
+	for _, row := range rows {
+		event := row.event
+		event.ModifiedAt = row.modified.Time
+
+		switch {
+		case row.deleted:
+			event.Type = models.ChangeDeleted
+		case createdAfter.IsZero(), row.created.Valid && row.created.Time.After(createdAfter):
+			event.Type = models.ChangeCreated
+		default:
+			event.Type = models.ChangeUpdated
+		}
+
+		events = append(events, event)
+		last = Watermark{Time: row.modified.Time, ID: event.ID}
+	}
+
+	return events, last, nil
+}
+
+// Run polls the feed every interval until ctx is canceled. Failed polls are logged
+// and retried on the next tick, from the last saved watermark.
+func (f *ChangeFeed) Run(ctx context.Context, handler ChangeHandler) error {
+	ticker := time.NewTicker(f.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := f.Poll(ctx, handler); err != nil && ctx.Err() == nil {
+			f.db.log().Warn("change feed poll failed, retrying", logger.String("feed", f.opts.Name), logger.Duration("interval", f.opts.Interval), logger.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Events runs the feed in the background and sends every event on the returned channel,
+// closed when ctx is canceled. A batch is acknowledged once all its events were received.
+func (f *ChangeFeed) Events(ctx context.Context) <-chan models.ChangeEvent {
+	ch := make(chan models.ChangeEvent)
+
+	go func() {
+		defer close(ch)
+
+		f.Run(ctx, func(ctx context.Context, events []models.ChangeEvent) error {
+			for _, event := range events {
+				select {
+				case ch <- event:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+	}()
+
+	return ch
+}
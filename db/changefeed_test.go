@@ -0,0 +1,193 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// changeTable is an in-memory contract table answering change feed queries
+type changeTable struct {
+	mu   sync.Mutex
+	rows map[int64]changeTableRow
+}
+
+type changeTableRow struct {
+	deleted  bool
+	modified *time.Time // nil = NULL
+}
+
+func (c *changeTable) set(id int64, row changeTableRow) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rows[id] = row
+}
+
+// handler answers the change feed queries: rows with a NULL LASTMODIFIED after the bound ID,
+// or rows on the (LASTMODIFIED, ID) keyset bound by the query
+func (c *changeTable) handler(t *testing.T) func(query string, args []driver.NamedValue) fakeResult {
+	return func(query string, args []driver.NamedValue) fakeResult {
+		if strings.Contains(query, "COALESCE") {
+			t.Errorf("modification time wrapped in %q", query)
+		}
+		unmodified := strings.Contains(query, contractColModified+" IS NULL")
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		type entry struct {
+			id       int64
+			modified time.Time
+			row      changeTableRow
+		}
+		var entries []entry
+		for id, row := range c.rows {
+			switch {
+			case unmodified:
+				if row.modified == nil && id > args[0].Value.(int64) {
+					entries = append(entries, entry{id, time.Time{}, row})
+				}
+			case row.modified != nil:
+				since, afterID := args[0].Value.(time.Time), args[2].Value.(int64)
+				if modified := *row.modified; modified.After(since) || (modified.Equal(since) && id > afterID) {
+					entries = append(entries, entry{id, modified, row})
+				}
+			}
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			if !entries[i].modified.Equal(entries[j].modified) {
+				return entries[i].modified.Before(entries[j].modified)
+			}
+			return entries[i].id < entries[j].id
+		})
+
+		var limit int
+		fmt.Sscanf(query[strings.Index(query, "FETCH NEXT"):], "FETCH NEXT %d", &limit)
+
+		result := fakeResult{cols: append(append([]string{}, contractColumns...), contractColCreated, contractColModified)}
+		for _, e := range entries[:min(limit, len(entries))] {
+			deleted := int64(0)
+			if e.row.deleted {
+				deleted = 1
+			}
+			var modified driver.Value
+			if e.row.modified != nil {
+				modified = *e.row.modified
+			}
+			result.rows = append(result.rows, []driver.Value{e.id, "Acme", nil, nil, nil, int64(0), deleted, nil, int64(0), int64(0), nil, modified})
+		}
+		return result
+	}
+}
+
+func eventIDs(events []models.ChangeEvent) string {
+	parts := make([]string, len(events))
+	for i, e := range events {
+		parts[i] = fmt.Sprintf("%d:%s", e.ID, e.Type)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestChangeFeedDeliversRowsWithoutModificationTime(t *testing.T) {
+	t1 := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+
+	table := &changeTable{rows: map[int64]changeTableRow{
+		1: {},
+		2: {},
+		3: {modified: &t1},
+		4: {modified: &t2, deleted: true},
+	}}
+	d, _ := newFakeDB(t, DialectOracle, config.DBConfig{}, table.handler(t))
+	feed := NewContractRepository(d).Changes(ChangeFeedOptions{BatchSize: 1})
+
+	var events []models.ChangeEvent
+	collectEvents := func(ctx context.Context, batch []models.ChangeEvent) error {
+		events = append(events, batch...)
+		return nil
+	}
+
+	n, err := feed.Poll(context.Background(), collectEvents)
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if n != 4 || eventIDs(events) != "1:created 2:created 3:created 4:deleted" {
+		t.Fatalf("delivered %d: %s", n, eventIDs(events))
+	}
+	if !events[0].ModifiedAt.IsZero() || !events[2].ModifiedAt.Equal(t1) {
+		t.Fatalf("modification times %v, %v", events[0].ModifiedAt, events[2].ModifiedAt)
+	}
+
+	mark, _ := feed.Watermark(context.Background())
+	if !mark.Time.Equal(t2) || mark.ID != 4 || mark.UnmodifiedID != 2 {
+		t.Fatalf("watermark %+v", mark)
+	}
+
+	// Nothing new
+	events = nil
+	if n, err := feed.Poll(context.Background(), collectEvents); err != nil || n != 0 {
+		t.Fatalf("second poll delivered %d: %v", n, err)
+	}
+
+	// Once updated, a row without modification time is delivered again, and a row inserted
+	// without modification time after the watermark is delivered too
+	t3 := t2.Add(time.Hour)
+	table.set(1, changeTableRow{modified: &t3})
+	table.set(5, changeTableRow{})
+
+	if _, err := feed.Poll(context.Background(), collectEvents); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if eventIDs(events) != "5:updated 1:updated" {
+		t.Fatalf("delivered %s", eventIDs(events))
+	}
+
+	mark, _ = feed.Watermark(context.Background())
+	if !mark.Time.Equal(t3) || mark.ID != 1 || mark.UnmodifiedID != 5 {
+		t.Fatalf("watermark %+v", mark)
+	}
+}
+
+func TestChangeFeedWatermarkOnRowsWithoutModificationTime(t *testing.T) {
+	table := &changeTable{rows: map[int64]changeTableRow{1: {}, 2: {}}}
+	d, _ := newFakeDB(t, DialectOracle, config.DBConfig{}, table.handler(t))
+	feed := NewContractRepository(d).Changes(ChangeFeedOptions{BatchSize: 1})
+
+	calls := 0
+	_, err := feed.Poll(context.Background(), func(ctx context.Context, batch []models.ChangeEvent) error {
+		calls++
+		if calls == 2 {
+			return fmt.Errorf("handler down")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected the handler error")
+	}
+
+	// The first row was accepted: the feed resumes after it
+	mark, _ := feed.Watermark(context.Background())
+	if !mark.Time.IsZero() || mark.ID != 0 || mark.UnmodifiedID != 1 {
+		t.Fatalf("watermark %+v", mark)
+	}
+
+	var events []models.ChangeEvent
+	if _, err := feed.Poll(context.Background(), func(ctx context.Context, batch []models.ChangeEvent) error {
+		events = append(events, batch...)
+		return nil
+	}); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	// Nothing with a modification time was delivered yet: the first pass goes on as created
+	if eventIDs(events) != "2:created" {
+		t.Fatalf("delivered %s", eventIDs(events))
+	}
+}
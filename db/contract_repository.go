@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"iter"
+	"strconv"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// ContractRepository reads contracts directly from the ZR database.
+// It is read-only: writes go through the ZR web service.
+type ContractRepository struct {
+	db *DB
+	q  Querier // Transaction the repository is bound to, nil to use the connection
+}
+
+// NewContractRepository creates a contract repository
+func NewContractRepository(db *DB) *ContractRepository {
+	return &ContractRepository{db: db}
+}
+
+// querier returns the transaction the repository is bound to, or the connection
+func (r *ContractRepository) querier(ctx context.Context) (Querier, error) {
+	if r.q != nil {
+		return r.q, nil
+	}
+	return r.db.querier(ctx)
+}
+
+// GetByID reads a contract by ID
+func (r *ContractRepository) GetByID(ctx context.Context, contractID int) (*models.ContractDetail, error) {
+	conn, err := r.querier(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q := newSelect(r.db.dialect, contractTable, contractColumns).Where(contractColID+" = %s", contractID)
+	query := q.String()
+
+	var row contractRow
+	err = conn.QueryRowContext(ctx, query, q.Args()...).Scan(row.targets()...)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("contract not found", "contract", strconv.Itoa(contractID))
+	}
+	if err != nil {
+		r.db.log().Error("failed to read contract", logger.Int("contract_id", contractID), logger.Error(err))
+		return nil, wrapError("failed to read contract", query, err)
+	}
+
+	return row.toDetail(), nil
+}
+
+// List reads the contracts matching filter
+func (r *ContractRepository) List(ctx context.Context, filter models.ContractFilter) ([]models.ContractList, error) {
+	contracts, err := collect(r.Iter(ctx, filter))
+	if err != nil {
+		r.db.log().Error("failed to list contracts", logger.Error(err))
+		return nil, err
+	}
+	return contracts, nil
+}
+
+// Iter streams the contracts matching filter, reading rows as the sequence is consumed
+func (r *ContractRepository) Iter(ctx context.Context, filter models.ContractFilter) iter.Seq2[models.ContractList, error] {
+	return iterContracts(ctx, r, filter, (*contractRow).toList)
+}
+
+// IterDetails streams the detail of the contracts matching filter
+func (r *ContractRepository) IterDetails(ctx context.Context, filter models.ContractFilter) iter.Seq2[models.ContractDetail, error] {
+	return iterContracts(ctx, r, filter, func(row *contractRow) models.ContractDetail { return *row.toDetail() })
+}
+
+// FindByReference returns the IDs of the contracts whose reference field holds ref.
+// Only the memo is stored in the contract table: ok is false for other reference fields.
+func (r *ContractRepository) FindByReference(ctx context.Context, field models.ContractRefField, ref string) (ids []int, ok bool, err error) {
+	if field != models.ContractRefMemo {
+		return nil, false, nil
+	}
+
+	conn, err := r.querier(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	q := newSelect(r.db.dialect, contractTable, []string{contractColID}).
+		Where(contractColMemo+" = %s", ref).
+		OrderBy(contractColID, false)
+
+	ids, err = collect(streamRows(ctx, r.db, conn, q.String(), q.Args(), func(rows *sql.Rows) (int, error) {
+		var id int
+		err := rows.Scan(&id)
+		return id, err
+	}))
+	if err != nil {
+		r.db.log().Error("failed to find contracts by reference", logger.Error(err))
+		return nil, false, err
+	}
+
+	return ids, true, nil
+}
+
+// iterContracts streams the contract rows matching filter, mapped by mapRow
+func iterContracts[T any](ctx context.Context, r *ContractRepository, filter models.ContractFilter, mapRow func(*contractRow) T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		conn, err := r.querier(ctx)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+
+		q := contractQuery(r.db.dialect, filter)
+
+		for value, err := range streamRows(ctx, r.db, conn, q.String(), q.Args(), func(rows *sql.Rows) (T, error) {
+			var row contractRow
+			if err := rows.Scan(row.targets()...); err != nil {
+				var zero T
+				return zero, err
+			}
+			return mapRow(&row), nil
+		}) {
+			if !yield(value, err) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// contractValues returns a contract row in contractColumns order
+func contractValues(id int64, name, memo string) []driver.Value {
+	return []driver.Value{id, name, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "2025-12-31", "3", int64(1), int64(0), memo, int64(4), int64(2)}
+}
+
+func TestContractFindByReference(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		return fakeResult{cols: []string{contractColID}, rows: [][]driver.Value{{int64(12)}, {int64(40)}}}
+	})
+	repo := NewContractRepository(d)
+
+	ids, ok, err := repo.FindByReference(context.Background(), models.ContractRefMemo, "crm-1")
+	if err != nil || !ok {
+		t.Fatalf("FindByReference: %v, ok %v", err, ok)
+	}
+	if fmt.Sprint(ids) != "[12 40]" {
+		t.Fatalf("got IDs %v", ids)
+	}
+
+	statements := drv.recorded()
+	if len(statements) != 1 || !strings.Contains(statements[0], "MEMO = :1") {
+		t.Fatalf("unexpected statements %q", statements)
+	}
+	if drv.args[0][0].Value != "crm-1" {
+		t.Fatalf("bound %v", drv.args[0][0].Value)
+	}
+
+	// Reference fields missing from the contract table cannot be looked up
+	if _, ok, err := repo.FindByReference(context.Background(), models.ContractRefIDNo, "crm-1"); ok || err != nil {
+		t.Fatalf("idNo lookup: ok %v, err %v", ok, err)
+	}
+}
+
+func TestContractGetByID(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		return fakeResult{cols: contractColumns, rows: [][]driver.Value{contractValues(12, "Acme", "crm-1")}}
+	})
+
+	detail, err := NewContractRepository(d).GetByID(context.Background(), 12)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	c := detail.Contract
+	if *c.ID != 12 || c.Name != "Acme" || c.ValidFrom != "2024-01-01" || c.ValidUntil != "2025-12-31" || c.FilialID != "3" {
+		t.Fatalf("got contract %+v", c)
+	}
+	if detail.Memo != "crm-1" || !detail.IsBlocked() || detail.IsDeleted() || detail.Counting != 4 || detail.Present != 2 {
+		t.Fatalf("got detail %+v", detail)
+	}
+	if statements := drv.recorded(); !strings.HasSuffix(statements[0], "FROM CONTRACT WHERE CONTRACTID = :1") {
+		t.Fatalf("unexpected query %q", statements[0])
+	}
+}
+
+func TestContractGetByIDNotFound(t *testing.T) {
+	d, _ := newFakeDB(t, DialectOracle, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		return fakeResult{cols: contractColumns}
+	})
+
+	if _, err := NewContractRepository(d).GetByID(context.Background(), 12); !errors.IsNotFoundError(err) {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestContractListFilters(t *testing.T) {
+	d, drv := newFakeDB(t, DialectPostgres, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		return fakeResult{cols: contractColumns, rows: [][]driver.Value{contractValues(1, "Acme", ""), contractValues(2, "Acme 2", "")}}
+	})
+
+	contracts, err := NewContractRepository(d).List(context.Background(), models.ContractFilter{
+		NamePrefix: "ac_",
+		FilialID:   "3",
+		MinID:      1,
+		Archived:   models.ArchivedExclude,
+		SortBy:     models.ContractSortByName,
+		SortDesc:   true,
+		Limit:      20,
+		Offset:     40,
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(contracts) != 2 || contracts[1].Name != "Acme 2" || contracts[0].ValidFrom != "2024-01-01" {
+		t.Fatalf("got %+v", contracts)
+	}
+
+	query := drv.recorded()[0]
+	for _, part := range []string{
+		"UPPER(NAME) LIKE $1 ESCAPE",
+		"FILIALID = $2",
+		"CONTRACTID >= $3",
+		"(DELETED IS NULL OR DELETED = 0)",
+		"ORDER BY NAME DESC, CONTRACTID LIMIT 20 OFFSET 40",
+	} {
+		if !strings.Contains(query, part) {
+			t.Fatalf("%q missing from %q", part, query)
+		}
+	}
+	if drv.args[0][0].Value != `AC\_%` {
+		t.Fatalf("name pattern %v", drv.args[0][0].Value)
+	}
+}
+
+func TestContractIterStopsEarly(t *testing.T) {
+	d, _ := newFakeDB(t, DialectOracle, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		return fakeResult{cols: contractColumns, rows: [][]driver.Value{contractValues(1, "A", ""), contractValues(2, "B", ""), contractValues(3, "C", "")}}
+	})
+
+	var names []string
+	for contract, err := range NewContractRepository(d).Iter(context.Background(), models.ContractFilter{}) {
+		if err != nil {
+			t.Fatalf("Iter: %v", err)
+		}
+		names = append(names, contract.Name)
+		if len(names) == 2 {
+			break
+		}
+	}
+	if fmt.Sprint(names) != "[A B]" {
+		t.Fatalf("got %v", names)
+	}
+
+	// The connection was released by the early stop
+	if stats := d.Raw().Stats(); stats.InUse != 0 {
+		t.Fatalf("%d connections still in use", stats.InUse)
+	}
+}
+
+func TestContractListWrapsDriverErrors(t *testing.T) {
+	d, _ := newFakeDB(t, DialectOracle, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		return fakeResult{err: stderrors.New("ORA-00904: invalid identifier")}
+	})
+
+	_, err := NewContractRepository(d).List(context.Background(), models.ContractFilter{FilialID: "3"})
+
+	var dbErr *errors.DatabaseError
+	if !stderrors.As(err, &dbErr) || dbErr.Operation != "SELECT" || strings.Contains(dbErr.Query, "'3'") {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,302 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+)
+
+// Querier is implemented by *sql.DB and *sql.Tx
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// DB is a lazily opened connection to the ZR database
+type DB struct {
+	cfg     config.DBConfig
+	dialect Dialect
+	logger  logger.Logger
+
+	mu         sync.Mutex
+	conn       *sql.DB
+	owned      bool          // Connection opened by the SDK, closed by Close
+	connecting chan struct{} // Closed when the connection attempt in progress ends, nil when none
+
+	stop chan struct{} // Stops the health check goroutine
+	wg   sync.WaitGroup
+
+	metrics  atomic.Pointer[MetricsHook]     // Receives query stats, may be nil
+	settings atomic.Pointer[config.DBConfig] // Statement, logging and pool settings, replaced by SetConfig
+
+	password string // Password resolved from a secret reference by Open, never logged
+
+	schema    atomic.Pointer[schemaState] // Detected on first connection, nil until then
+	detecting chan struct{}               // Closed when the detection in progress ends, nil when none
+	readOnly  atomic.Bool                 // Degraded mode, write statements are refused
+}
+
+// Open prepares a database handle. No connection is made until the first query.
+func Open(cfg config.DBConfig, log logger.Logger) (*DB, error) {
+	dialect, err := ResolveDialect(cfg)
+	if err != nil {
+		return nil, errors.NewSDKError(errors.ErrorTypeValidation, "invalid database configuration", err)
+	}
+
+	d := &DB{
+		cfg:     cfg,
+		dialect: dialect,
+		logger:  log,
+	}
+	d.settings.Store(&cfg)
+
+	// A password reference is resolved now so that a broken reference fails client creation
+	if config.IsSecretRef(cfg.Password) {
+		password, err := config.ResolveSecret(context.Background(), cfg.Password)
+		if err != nil {
+			return nil, errors.NewDatabaseError("failed to resolve database password", "", "OPEN", err)
+		}
+		d.password = password
+	}
+
+	return d, nil
+}
+
+// NewFromConn wraps an existing connection, e.g. sqlite or a fake driver in tests.
+// Only the statement and logging settings of cfg are used, the pool is left untouched.
+// The caller keeps ownership: Close does not close it.
+func NewFromConn(conn *sql.DB, dialect Dialect, cfg config.DBConfig, log logger.Logger) *DB {
+	d := &DB{
+		cfg:     cfg,
+		dialect: dialect,
+		logger:  log,
+		conn:    conn,
+	}
+	d.settings.Store(&cfg)
+	return d
+}
+
+// SetConfig applies the runtime settings of cfg: statement timeout, slow query threshold,
+// masked columns and, on a connection opened by the SDK, the pool limits.
+// Connection settings (driver, host, credentials) only apply to a new DB.
+func (d *DB) SetConfig(cfg config.DBConfig) {
+	if d == nil {
+		return
+	}
+
+	d.settings.Store(&cfg)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.conn != nil && d.owned {
+		applyPool(d.conn, cfg)
+	}
+}
+
+// Dialect returns the SQL dialect of the database
+func (d *DB) Dialect() Dialect {
+	return d.dialect
+}
+
+// Conn returns the underlying connection, opening it and checking the ZR schema version on first use
+func (d *DB) Conn(ctx context.Context) (*sql.DB, error) {
+	if d == nil {
+		return nil, errors.NewDatabaseError("database is not configured", "", "OPEN", nil)
+	}
+
+	conn, err := d.connection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.single(ctx, &d.detecting, "failed to detect ZR schema version", "SELECT",
+		func() bool { return d.schema.Load() != nil },
+		func() error { return d.detectSchema(ctx, loggedQuerier{q: conn, db: d}) },
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if state := d.schema.Load(); state.err != nil {
+		return nil, state.err
+	}
+
+	return conn, nil
+}
+
+// connection returns the connection, opening it on first use
+func (d *DB) connection(ctx context.Context) (*sql.DB, error) {
+	for {
+		err := d.single(ctx, &d.connecting, "failed to connect to database", "PING",
+			func() bool { return d.conn != nil },
+			func() error {
+				conn, err := d.open(ctx)
+				if err != nil {
+					return err
+				}
+
+				d.mu.Lock()
+				defer d.mu.Unlock()
+
+				d.conn = conn
+				d.owned = true
+
+				if d.cfg.HealthCheckInterval > 0 {
+					d.startHealthCheck(conn, d.cfg.HealthCheckInterval)
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// Closed in the meantime: open again
+		if conn := d.Raw(); conn != nil {
+			return conn, nil
+		}
+	}
+}
+
+// single runs attempt unless done, checked with d.mu held, reports it already succeeded.
+// The attempt runs without d.mu held, so the database is never reached under the lock.
+// Concurrent callers wait for the attempt in progress and make their own if it failed.
+func (d *DB) single(ctx context.Context, pending *chan struct{}, message, operation string, done func() bool, attempt func() error) error {
+	for {
+		d.mu.Lock()
+		if done() {
+			d.mu.Unlock()
+			return nil
+		}
+
+		if wait := *pending; wait != nil {
+			d.mu.Unlock()
+			select {
+			case <-wait:
+				continue
+			case <-ctx.Done():
+				return errors.NewDatabaseError(message, "", operation, ctx.Err())
+			}
+		}
+
+		flight := make(chan struct{})
+		*pending = flight
+		d.mu.Unlock()
+
+		err := attempt()
+
+		d.mu.Lock()
+		*pending = nil
+		close(flight)
+		d.mu.Unlock()
+
+		return err
+	}
+}
+
+// log returns the logger, a no-op logger when the database is not configured
+func (d *DB) log() logger.Logger {
+	if d == nil {
+		return logger.NewNoOpLogger()
+	}
+	return d.logger
+}
+
+// querier returns the connection, opening it on first use, with statements logged and measured
+func (d *DB) querier(ctx context.Context) (Querier, error) {
+	conn, err := d.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return loggedQuerier{q: conn, db: d}, nil
+}
+
+// open connects to the database and checks the connection
+func (d *DB) open(ctx context.Context) (*sql.DB, error) {
+	d.logger.Debug("connecting to database",
+		logger.String("driver", d.cfg.DriverName()),
+		logger.String("host", d.cfg.Host),
+		logger.Int("port", d.cfg.Port),
+		logger.String("database", d.cfg.Database),
+	)
+
+	conn, err := d.openConn()
+	if err != nil {
+		return nil, err
+	}
+
+	applyPool(conn, *d.settings.Load())
+
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return nil, errors.NewDatabaseError("failed to connect to database", "", "PING", err)
+	}
+
+	d.logger.Info("database connection established", logger.String("dialect", string(d.dialect)))
+
+	return conn, nil
+}
+
+// openConn prepares the connection pool, through a secretConnector when the password is a secret reference
+func (d *DB) openConn() (*sql.DB, error) {
+	if config.IsSecretRef(d.cfg.Password) {
+		connector, err := d.newSecretConnector(d.password)
+		if err != nil {
+			return nil, err
+		}
+		return sql.OpenDB(connector), nil
+	}
+
+	dsn, err := d.dsn(config.PlainSecret(d.cfg.Password))
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := sql.Open(d.cfg.DriverName(), dsn)
+	if err != nil {
+		// The DSN carries the password, never wrap the driver error verbatim
+		return nil, errors.NewDatabaseError("failed to open database, is driver \""+d.cfg.DriverName()+"\" imported?", "", "OPEN", nil)
+	}
+	return conn, nil
+}
+
+// Raw returns the connection if it is already open, without opening it.
+// Statements run on it directly are neither logged nor refused in degraded read-only mode.
+func (d *DB) Raw() *sql.DB {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.conn
+}
+
+// Close closes the connection if it was opened
+func (d *DB) Close() error {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.stopHealthCheck()
+
+	if d.conn == nil || !d.owned {
+		return nil
+	}
+
+	err := d.conn.Close()
+	d.conn = nil
+	if err != nil {
+		return errors.NewDatabaseError("failed to close database connection", "", "CLOSE", err)
+	}
+	return nil
+}
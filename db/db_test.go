@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+)
+
+// connectDriver is registered as "zrfake" so that Open can reach it
+var connectDriver = &fakeDriver{schemaVersion: "12.4.1-b3"}
+
+func init() {
+	sql.Register("zrfake", connectDriver)
+}
+
+func TestConnConnectsOnceWithoutHoldingTheLock(t *testing.T) {
+	connectDriver.pinging = make(chan struct{}, 4)
+	connectDriver.release = make(chan struct{})
+	t.Cleanup(func() { connectDriver.pinging, connectDriver.release = nil, nil })
+
+	d, err := Open(config.DBConfig{Driver: "zrfake", Dialect: "oracle", Host: "zr", Port: 1521, Database: "ZR"}, logger.NewNoOpLogger())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+
+	var wg sync.WaitGroup
+	conns := make([]*sql.DB, 3)
+	errs := make([]error, 3)
+	for i := range conns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conns[i], errs[i] = d.Conn(context.Background())
+		}()
+	}
+
+	select {
+	case <-connectDriver.pinging:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no ping")
+	}
+
+	// The handle stays usable while the database is being reached
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if d.Raw() != nil {
+			t.Error("Raw returned a connection before the ping succeeded")
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Raw blocked during the ping")
+	}
+
+	close(connectDriver.release)
+	wg.Wait()
+
+	for i, conn := range conns {
+		if errs[i] != nil {
+			t.Fatalf("Conn %d: %v", i, errs[i])
+		}
+		if conn != conns[0] || conn == nil {
+			t.Fatalf("Conn %d returned another connection", i)
+		}
+	}
+	if extra := len(connectDriver.pinging); extra != 0 {
+		t.Fatalf("%d extra pings", extra)
+	}
+	if !d.Capabilities().Detected {
+		t.Fatal("schema version not detected")
+	}
+}
+
+func TestConnCanceledWhileWaiting(t *testing.T) {
+	connectDriver.pinging = make(chan struct{}, 4)
+	connectDriver.release = make(chan struct{})
+	t.Cleanup(func() { connectDriver.pinging, connectDriver.release = nil, nil })
+
+	d, err := Open(config.DBConfig{Driver: "zrfake", Dialect: "oracle", Host: "zr", Port: 1521, Database: "ZR"}, logger.NewNoOpLogger())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := d.Conn(context.Background())
+		first <- err
+	}()
+	<-connectDriver.pinging
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := d.Conn(ctx); err == nil {
+		t.Fatal("expected an error from a canceled waiter")
+	}
+
+	close(connectDriver.release)
+	if err := <-first; err != nil {
+		t.Fatalf("first Conn: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+)
+
+// Dialect is the SQL flavour spoken by the database
+type Dialect string
+
+const (
+	DialectOracle   Dialect = "oracle"
+	DialectPostgres Dialect = "postgres"
+	DialectGeneric  Dialect = "generic" // "?" placeholders, e.g. sqlite in tests
+)
+
+// Placeholder returns the bind parameter marker for the n-th (1-based) argument
+func (d Dialect) Placeholder(n int) string {
+	switch d {
+	case DialectOracle:
+		return ":" + strconv.Itoa(n)
+	case DialectPostgres:
+		return "$" + strconv.Itoa(n)
+	default:
+		return "?"
+	}
+}
+
+// ResolveDialect returns the dialect configured for the database, derived from the driver name when unset
+func ResolveDialect(cfg config.DBConfig) (Dialect, error) {
+	name := strings.ToLower(cfg.Dialect)
+	if name == "" {
+		name = strings.ToLower(cfg.DriverName())
+	}
+
+	switch name {
+	case "oracle", "godror", "goracle", "oci8":
+		return DialectOracle, nil
+	case "postgres", "postgresql", "pgx":
+		return DialectPostgres, nil
+	case "generic", "sqlite", "sqlite3":
+		return DialectGeneric, nil
+	default:
+		return "", fmt.Errorf("unsupported database dialect: %q", name)
+	}
+}
+
+// EZConnect returns the Oracle Easy Connect string "host:port/service"
+func EZConnect(cfg config.DBConfig) string {
+	return net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)) + "/" + cfg.Database
+}
+
+// BuildDSN builds the data source name expected by the configured driver
+func BuildDSN(cfg config.DBConfig) (string, error) {
+	dialect, err := ResolveDialect(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	switch dialect {
+	case DialectOracle:
+		return buildOracleDSN(cfg), nil
+	case DialectPostgres:
+		return buildPostgresDSN(cfg), nil
+	default:
+		return "", fmt.Errorf("no DSN format for dialect %q, inject the connection instead", dialect)
+	}
+}
+
+// buildOracleDSN builds an Oracle DSN around the Easy Connect string.
+// godror takes logfmt parameters, go-ora (and most other drivers) an oracle:// URL.
+func buildOracleDSN(cfg config.DBConfig) string {
+	if strings.EqualFold(cfg.Driver, "godror") {
+		return fmt.Sprintf("user=%s password=%s connectString=%s",
+			quoteLogfmt(cfg.Username),
+			quoteLogfmt(cfg.Password),
+			quoteLogfmt(EZConnect(cfg)),
+		)
+	}
+
+	u := url.URL{
+		Scheme: "oracle",
+		User:   url.UserPassword(cfg.Username, cfg.Password),
+		Host:   net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Path:   "/" + cfg.Database,
+	}
+	return u.String()
+}
+
+// buildPostgresDSN builds a keyword/value Postgres connection string
+func buildPostgresDSN(cfg config.DBConfig) string {
+	pairs := []string{
+		"host=" + quotePostgres(cfg.Host),
+		"port=" + strconv.Itoa(cfg.Port),
+		"user=" + quotePostgres(cfg.Username),
+		"password=" + quotePostgres(cfg.Password),
+		"dbname=" + quotePostgres(cfg.Database),
+		"sslmode=" + quotePostgres(cfg.SSL()),
+	}
+	return strings.Join(pairs, " ")
+}
+
+// quotePostgres quotes a keyword/value connection string value when needed
+func quotePostgres(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	return "'" + escaped + "'"
+}
+
+// quoteLogfmt quotes a logfmt value
+func quoteLogfmt(value string) string {
+	return strconv.Quote(value)
+}
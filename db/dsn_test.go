@@ -0,0 +1,56 @@
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+)
+
+func TestResolveDialect(t *testing.T) {
+	tests := []struct {
+		cfg  config.DBConfig
+		want Dialect
+	}{
+		{config.DBConfig{}, DialectOracle},
+		{config.DBConfig{Driver: "godror"}, DialectOracle},
+		{config.DBConfig{Driver: "pgx"}, DialectPostgres},
+		{config.DBConfig{Dialect: "postgres"}, DialectPostgres},
+	}
+
+	for _, tt := range tests {
+		got, err := ResolveDialect(tt.cfg)
+		if err != nil || got != tt.want {
+			t.Errorf("ResolveDialect(%+v) = %q, %v; want %q", tt.cfg, got, err, tt.want)
+		}
+	}
+}
+
+func TestBuildPostgresDSNSSLMode(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.DBConfig
+		want string
+	}{
+		{"default", config.DBConfig{}, "sslmode=disable"},
+		{"deprecated flag", config.DBConfig{SSLMode: true}, "sslmode=require"},
+		{"policy", config.DBConfig{SSLPolicy: config.SSLPolicyVerifyFull}, "sslmode=verify-full"},
+		{"policy wins over flag", config.DBConfig{SSLMode: true, SSLPolicy: config.SSLPolicyDisable}, "sslmode=disable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Dialect = "postgres"
+			tt.cfg.Host = "zr"
+			tt.cfg.Port = 5432
+
+			dsn, err := BuildDSN(tt.cfg)
+			if err != nil {
+				t.Fatalf("BuildDSN: %v", err)
+			}
+			if !strings.Contains(dsn, tt.want) {
+				t.Fatalf("dsn %q does not contain %q", dsn, tt.want)
+			}
+		})
+	}
+}
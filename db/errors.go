@@ -0,0 +1,138 @@
+package db
+
+import (
+	stderrors "errors"
+	"regexp"
+	"strings"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+)
+
+// Driver error codes of transient transaction conflicts
+var (
+	oracleRetryableCodes   = []string{"ORA-00060", "ORA-08177"} // deadlock, serialization failure
+	postgresRetryableCodes = []string{"40001", "40P01"}         // serialization_failure, deadlock_detected
+)
+
+// Driver error codes of rejected credentials
+var (
+	oracleAuthCodes   = []string{"ORA-01017", "ORA-01005"} // invalid username/password, null password
+	postgresAuthCodes = []string{"28P01", "28000"}         // invalid_password, invalid_authorization_specification
+)
+
+// Driver error codes of a missing table
+var (
+	oracleMissingTableCodes   = []string{"ORA-00942"} // table or view does not exist
+	postgresMissingTableCodes = []string{"42P01"}     // undefined_table
+)
+
+// IsRetryableTxError reports whether err is a deadlock or serialization failure,
+// after which the whole transaction can be retried
+func IsRetryableTxError(err error) bool {
+	return hasErrorCode(err, oracleRetryableCodes, postgresRetryableCodes)
+}
+
+// IsAuthError reports whether err is the database rejecting the credentials
+func IsAuthError(err error) bool {
+	return hasErrorCode(err, oracleAuthCodes, postgresAuthCodes)
+}
+
+// isMissingTableError reports whether err is the database reporting an unknown table
+func isMissingTableError(err error) bool {
+	return hasErrorCode(err, oracleMissingTableCodes, postgresMissingTableCodes)
+}
+
+// hasErrorCode reports whether err carries one of the Oracle or Postgres (SQLSTATE) codes
+func hasErrorCode(err error, oracleCodes, postgresCodes []string) bool {
+	if err == nil {
+		return false
+	}
+
+	// pgx / pgconn
+	var stateErr interface{ SQLState() string }
+	if stderrors.As(err, &stateErr) && containsCode(postgresCodes, stateErr.SQLState()) {
+		return true
+	}
+
+	// lib/pq: Get('C') returns the SQLSTATE code
+	var fieldErr interface{ Get(k byte) string }
+	if stderrors.As(err, &fieldErr) && containsCode(postgresCodes, fieldErr.Get('C')) {
+		return true
+	}
+
+	// Oracle drivers (go-ora, godror) and drivers only reporting the code in their message
+	for e := err; e != nil; e = stderrors.Unwrap(e) {
+		message := e.Error()
+		for _, code := range oracleCodes {
+			if strings.Contains(message, code) {
+				return true
+			}
+		}
+		for _, code := range postgresCodes {
+			if strings.Contains(message, "SQLSTATE "+code) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func containsCode(codes []string, code string) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// wrapError converts a driver error into a DatabaseError carrying the operation and sanitized query
+func wrapError(message, query string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// Already wrapped further down
+	var dbErr *errors.DatabaseError
+	if stderrors.As(err, &dbErr) {
+		return err
+	}
+
+	if IsRetryableTxError(err) {
+		message += " (transaction conflict)"
+	}
+
+	return errors.NewDatabaseError(message, SanitizeQuery(query), operationOf(query), err)
+}
+
+var (
+	stringLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+	numberLiteral = regexp.MustCompile(`(^|[^\w:$.])\d+(?:\.\d+)?\b`) // Keeps :1 / $1 placeholders
+	whitespace    = regexp.MustCompile(`\s+`)
+)
+
+// maxQueryLength bounds the query text kept in errors and logs
+const maxQueryLength = 2000
+
+// SanitizeQuery replaces inline literals by "?" and collapses whitespace, so queries
+// can be logged and attached to errors without leaking values
+func SanitizeQuery(query string) string {
+	query = stringLiteral.ReplaceAllString(query, "?")
+	query = numberLiteral.ReplaceAllString(query, "${1}?")
+	query = strings.TrimSpace(whitespace.ReplaceAllString(query, " "))
+
+	if len(query) > maxQueryLength {
+		query = query[:maxQueryLength] + "..."
+	}
+	return query
+}
+
+// operationOf returns the SQL verb of a query (SELECT, INSERT, ...)
+func operationOf(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,227 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+)
+
+// fakeResult is the answer of the fake driver to a statement
+type fakeResult struct {
+	cols     []string
+	rows     [][]driver.Value
+	affected int64
+	err      error
+}
+
+// fakeDriver answers statements with a handler and records them.
+// Schema version queries are answered with schemaVersion, "" meaning no DBVERSION table.
+type fakeDriver struct {
+	mu            sync.Mutex
+	handler       func(query string, args []driver.NamedValue) fakeResult
+	schemaVersion string
+	statements    []string
+	args          [][]driver.NamedValue
+
+	pinging  chan struct{} // Signalled when a ping starts, nil to skip
+	release  chan struct{} // Ping waits until closed, nil to answer at once
+	failPing atomic.Bool   // Pings fail while set
+
+	deadlines atomic.Int32 // Statements run with a context deadline
+
+	versionStarted chan struct{} // Signalled when a schema version query starts, nil to skip
+	versionRelease chan struct{} // Schema version queries wait until closed, nil to answer at once
+	versionErr     error         // Schema version queries fail with it when set
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+func (d *fakeDriver) Connect(context.Context) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+func (d *fakeDriver) Driver() driver.Driver { return d }
+
+func (d *fakeDriver) record(query string, args []driver.NamedValue) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = append(d.statements, query)
+	d.args = append(d.args, args)
+}
+
+// recorded returns the statements run so far
+func (d *fakeDriver) recorded() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.statements...)
+}
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return c, nil }
+
+func (c *fakeConn) Ping(ctx context.Context) error {
+	if c.d.pinging != nil {
+		c.d.pinging <- struct{}{}
+	}
+	if c.d.release != nil {
+		select {
+		case <-c.d.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if c.d.failPing.Load() {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+func (c *fakeConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if opts.ReadOnly {
+		c.d.record("BEGIN READ ONLY", nil)
+	} else {
+		c.d.record("BEGIN", nil)
+	}
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.d.record("COMMIT", nil)
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.d.record("ROLLBACK", nil)
+	return nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if strings.Contains(query, versionTable) {
+		if c.d.versionStarted != nil {
+			c.d.versionStarted <- struct{}{}
+		}
+		if c.d.versionRelease != nil {
+			<-c.d.versionRelease
+		}
+		if c.d.versionErr != nil {
+			return nil, c.d.versionErr
+		}
+		if c.d.schemaVersion == "" {
+			return nil, errors.New("ORA-00942: table or view does not exist")
+		}
+		return &fakeRows{result: fakeResult{cols: []string{"VERSION"}, rows: [][]driver.Value{{"11.0"}, {c.d.schemaVersion}}}}, nil
+	}
+
+	c.d.record(query, args)
+	c.d.countDeadline(ctx)
+	result := c.d.handle(query, args)
+	if result.err != nil {
+		return nil, result.err
+	}
+	return &fakeRows{result: result}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.d.record(query, args)
+	c.d.countDeadline(ctx)
+	result := c.d.handle(query, args)
+	if result.err != nil {
+		return nil, result.err
+	}
+	return driver.RowsAffected(result.affected), nil
+}
+
+func (d *fakeDriver) countDeadline(ctx context.Context) {
+	if _, ok := ctx.Deadline(); ok {
+		d.deadlines.Add(1)
+	}
+}
+
+func (d *fakeDriver) handle(query string, args []driver.NamedValue) fakeResult {
+	if d.handler == nil {
+		return fakeResult{}
+	}
+	return d.handler(query, args)
+}
+
+type fakeRows struct {
+	result fakeResult
+	next   int
+}
+
+func (r *fakeRows) Columns() []string { return r.result.cols }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.next])
+	r.next++
+	return nil
+}
+
+// newFakeDB returns a DB over the fake driver, with a supported schema version
+func newFakeDB(t *testing.T, dialect Dialect, cfg config.DBConfig, handler func(query string, args []driver.NamedValue) fakeResult) (*DB, *fakeDriver) {
+	t.Helper()
+
+	drv := &fakeDriver{handler: handler, schemaVersion: "12.4.1-b3"}
+	conn := sql.OpenDB(drv)
+	t.Cleanup(func() { conn.Close() })
+
+	return NewFromConn(conn, dialect, cfg, logger.NewNoOpLogger()), drv
+}
+
+// logEntry is a message written to a recordLogger
+type logEntry struct {
+	level  string
+	msg    string
+	fields []logger.Field
+}
+
+// recordLogger records the messages logged through it
+type recordLogger struct {
+	logger.NoOpLogger
+
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordLogger) record(level, msg string, fields []logger.Field) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, logEntry{level: level, msg: msg, fields: fields})
+}
+
+func (l *recordLogger) Debug(msg string, fields ...logger.Field) { l.record("debug", msg, fields) }
+func (l *recordLogger) Info(msg string, fields ...logger.Field)  { l.record("info", msg, fields) }
+func (l *recordLogger) Warn(msg string, fields ...logger.Field)  { l.record("warn", msg, fields) }
+func (l *recordLogger) Error(msg string, fields ...logger.Field) { l.record("error", msg, fields) }
+
+func (l *recordLogger) With(...logger.Field) logger.Logger { return l }
+
+// find returns the entries logged with msg
+func (l *recordLogger) find(msg string) []logEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var found []logEntry
+	for _, e := range l.entries {
+		if e.msg == msg {
+			found = append(found, e)
+		}
+	}
+	return found
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"iter"
+	"strconv"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// ParticipantRepository reads participants (consumers) directly from the ZR database.
+// It is read-only: writes go through the ZR web service.
+type ParticipantRepository struct {
+	db *DB
+	q  Querier // Transaction the repository is bound to, nil to use the connection
+}
+
+// NewParticipantRepository creates a participant repository
+func NewParticipantRepository(db *DB) *ParticipantRepository {
+	return &ParticipantRepository{db: db}
+}
+
+// querier returns the transaction the repository is bound to, or the connection
+func (r *ParticipantRepository) querier(ctx context.Context) (Querier, error) {
+	if r.q != nil {
+		return r.q, nil
+	}
+	return r.db.querier(ctx)
+}
+
+// GetByID reads a participant of a contract by ID
+func (r *ParticipantRepository) GetByID(ctx context.Context, contractID, participantID int) (*models.ParticipantDetail, error) {
+	conn, err := r.querier(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q := newSelect(r.db.dialect, participantTable, participantColumns).
+		Where(participantColContractID+" = %s", contractID).
+		Where(participantColID+" = %s", participantID)
+	query := q.String()
+
+	var row participantRow
+	err = conn.QueryRowContext(ctx, query, q.Args()...).Scan(row.targets()...)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("participant not found", "participant", strconv.Itoa(participantID))
+	}
+	if err != nil {
+		r.db.log().Error("failed to read participant", logger.Int("contract_id", contractID), logger.Int("participant_id", participantID), logger.Error(err))
+		return nil, wrapError("failed to read participant", query, err)
+	}
+
+	return row.toDetail(), nil
+}
+
+// List reads the participants matching filter
+func (r *ParticipantRepository) List(ctx context.Context, filter models.ParticipantFilter) ([]models.ParticipantList, error) {
+	participants, err := collect(r.Iter(ctx, filter))
+	if err != nil {
+		r.db.log().Error("failed to list participants", logger.Int("contract_id", filter.ContractID), logger.Error(err))
+		return nil, err
+	}
+	return participants, nil
+}
+
+// Iter streams the participants matching filter, reading rows as the sequence is consumed
+func (r *ParticipantRepository) Iter(ctx context.Context, filter models.ParticipantFilter) iter.Seq2[models.ParticipantList, error] {
+	return iterParticipants(ctx, r, filter, (*participantRow).toList)
+}
+
+// IterDetails streams the detail (card, license plates, presence) of the participants matching filter
+func (r *ParticipantRepository) IterDetails(ctx context.Context, filter models.ParticipantFilter) iter.Seq2[models.ParticipantDetail, error] {
+	return iterParticipants(ctx, r, filter, func(row *participantRow) models.ParticipantDetail { return *row.toDetail() })
+}
+
+// FindOwner reads the first live participant matching filter, with the contract it belongs to.
+// Archived participants own no card or plate anymore and are skipped.
+func (r *ParticipantRepository) FindOwner(ctx context.Context, filter models.ParticipantFilter) (*models.ParticipantOwner, error) {
+	filter.Limit, filter.Offset = 1, 0
+	filter.Archived = models.ArchivedExclude
+
+	var detail *models.ParticipantDetail
+	for d, err := range r.IterDetails(ctx, filter) {
+		if err != nil {
+			r.db.log().Error("failed to find participant owner", logger.Error(err))
+			return nil, err
+		}
+		detail = &d
+	}
+	if detail == nil {
+		return nil, errors.NewNotFoundError("no participant matches", "participant", "")
+	}
+
+	contracts := &ContractRepository{db: r.db, q: r.q}
+	contract, err := contracts.GetByID(ctx, detail.Participant.ContractID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ParticipantOwner{Contract: contract.ToList(), Participant: *detail}, nil
+}
+
+// iterParticipants streams the participant rows matching filter, mapped by mapRow
+func iterParticipants[T any](ctx context.Context, r *ParticipantRepository, filter models.ParticipantFilter, mapRow func(*participantRow) T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		conn, err := r.querier(ctx)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+
+		q := participantQuery(r.db.dialect, filter)
+
+		for value, err := range streamRows(ctx, r.db, conn, q.String(), q.Args(), func(rows *sql.Rows) (T, error) {
+			var row participantRow
+			if err := rows.Scan(row.targets()...); err != nil {
+				var zero T
+				return zero, err
+			}
+			return mapRow(&row), nil
+		}) {
+			if !yield(value, err) {
+				return
+			}
+		}
+	}
+}
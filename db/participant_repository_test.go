@@ -0,0 +1,162 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// participantValues returns a participant row in participantColumns order
+func participantValues(id, contractID int64, name, cardNo string) []driver.Value {
+	return []driver.Value{id, contractID, name, nil, nil, nil, "3", int64(0), cardNo, int64(2), int64(0), int64(0), nil, nil, nil, nil, int64(0), int64(0), int64(0)}
+}
+
+func TestParticipantFindOwner(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		if strings.Contains(query, "FROM "+participantTable) {
+			return fakeResult{cols: participantColumns, rows: [][]driver.Value{participantValues(5, 9, "Bob", "111")}}
+		}
+		return fakeResult{cols: contractColumns, rows: [][]driver.Value{{int64(9), "Acme", nil, nil, "3", int64(0), int64(0), nil, int64(0), int64(0)}}}
+	})
+
+	owner, err := NewParticipantRepository(d).FindOwner(context.Background(), models.ParticipantFilter{CardNumber: "111", Limit: 50, Archived: models.ArchivedOnly})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner.Contract.ID != 9 || owner.Contract.Name != "Acme" || *owner.Participant.Participant.ID != 5 ||
+		owner.Participant.Identification == nil || owner.Participant.Identification.CardNo != "111" {
+		t.Fatalf("got %+v", owner)
+	}
+
+	statements := drv.recorded()
+	if len(statements) != 2 {
+		t.Fatalf("unexpected statements %q", statements)
+	}
+	if !strings.Contains(statements[0], participantColCardNo+" = :1") || !strings.HasSuffix(statements[0], "FETCH NEXT 1 ROWS ONLY") {
+		t.Fatalf("participant query %q", statements[0])
+	}
+	if !strings.Contains(statements[0], "("+participantColDeleted+" IS NULL OR "+participantColDeleted+" = 0)") {
+		t.Fatalf("archived participants are not skipped: %q", statements[0])
+	}
+	if !strings.Contains(statements[1], "FROM "+contractTable+" WHERE "+contractColID+" = :1") || drv.args[1][0].Value != int64(9) {
+		t.Fatalf("contract query %q %v", statements[1], drv.args[1])
+	}
+}
+
+func TestParticipantFindOwnerNotFound(t *testing.T) {
+	d, _ := newFakeDB(t, DialectOracle, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		return fakeResult{cols: participantColumns}
+	})
+
+	_, err := NewParticipantRepository(d).FindOwner(context.Background(), models.ParticipantFilter{CardNumber: "111"})
+	if !errors.IsNotFoundError(err) {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestParticipantListByLicensePlate(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		return fakeResult{cols: participantColumns, rows: [][]driver.Value{participantValues(5, 9, "Bob", "")}}
+	})
+
+	if _, err := NewParticipantRepository(d).List(context.Background(), models.ParticipantFilter{LicensePlate: "ab-12 3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	query := drv.recorded()[0]
+	for _, slot := range []string{participantColLPN1, participantColLPN2, participantColLPN3} {
+		if !strings.Contains(query, "REPLACE(REPLACE(REPLACE(REPLACE(UPPER("+slot+"), ' ', ''), '-', ''), '.', ''), '\t', '')") {
+			t.Fatalf("%s not normalized in %q", slot, query)
+		}
+	}
+	for i, arg := range drv.args[0] {
+		if arg.Value != "AB123" {
+			t.Fatalf("argument %d is %v, want the normalized plate", i, arg.Value)
+		}
+	}
+}
+
+func TestParticipantGetByID(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		row := participantValues(5, 9, "Bob", "111")
+		row[12] = "AB123"
+		return fakeResult{cols: participantColumns, rows: [][]driver.Value{row}}
+	})
+
+	detail, err := NewParticipantRepository(d).GetByID(context.Background(), 9, 5)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if *detail.Participant.ID != 5 || detail.Participant.ContractID != 9 || detail.Participant.Name != "Bob" || detail.LPN1 != "AB123" {
+		t.Fatalf("got %+v", detail)
+	}
+	if detail.Identification == nil || detail.Identification.CardNo != "111" || detail.Identification.CardClass != 2 {
+		t.Fatalf("got identification %+v", detail.Identification)
+	}
+
+	if query := drv.recorded()[0]; !strings.HasSuffix(query, "WHERE CONTRACTID = :1 AND CONSUMERID = :2") {
+		t.Fatalf("unexpected query %q", query)
+	}
+}
+
+func TestParticipantGetByIDWithoutCard(t *testing.T) {
+	d, _ := newFakeDB(t, DialectOracle, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		row := participantValues(5, 9, "Bob", "")
+		row[9] = int64(0)
+		return fakeResult{cols: participantColumns, rows: [][]driver.Value{row}}
+	})
+
+	detail, err := NewParticipantRepository(d).GetByID(context.Background(), 9, 5)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if detail.Identification != nil {
+		t.Fatalf("identification set without a card: %+v", detail.Identification)
+	}
+}
+
+func TestParticipantListFilters(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		return fakeResult{cols: participantColumns, rows: [][]driver.Value{participantValues(5, 9, "Bob", ""), participantValues(6, 9, "Eve", "")}}
+	})
+
+	present := models.PresenceState(1)
+	participants, err := NewParticipantRepository(d).List(context.Background(), models.ParticipantFilter{
+		ContractID: 9,
+		CardNumber: "111",
+		ActiveOn:   time.Date(2024, 5, 1, 15, 30, 0, 0, time.UTC),
+		Present:    &present,
+		Archived:   models.ArchivedOnly,
+		Limit:      10,
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(participants) != 2 || participants[1].Name != "Eve" || participants[0].ContractID != 9 {
+		t.Fatalf("got %+v", participants)
+	}
+
+	query := drv.recorded()[0]
+	for _, part := range []string{
+		"CONTRACTID = :1",
+		"CARDNO = :2",
+		"(VALIDFROM IS NULL OR VALIDFROM < :3)",
+		"(VALIDUNTIL IS NULL OR VALIDUNTIL >= :4)",
+		"PRESENT = :5",
+		"DELETED <> 0",
+		"ORDER BY CONTRACTID, CONSUMERID FETCH NEXT 10 ROWS ONLY",
+	} {
+		if !strings.Contains(query, part) {
+			t.Fatalf("%q missing from %q", part, query)
+		}
+	}
+	if day := drv.args[0][3].Value.(time.Time); !day.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("active day bound %v", day)
+	}
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+)
+
+// Pool defaults, applied when the configuration leaves a setting at zero
+const (
+	defaultMaxOpenConns    = 10
+	defaultMaxIdleConns    = 2
+	defaultConnMaxLifetime = 30 * time.Minute
+	defaultConnMaxIdleTime = 5 * time.Minute
+	healthPingTimeout      = 5 * time.Second
+)
+
+// applyPool bounds the connection pool of conn
+func applyPool(conn *sql.DB, cfg config.DBConfig) {
+	maxOpen := cfg.MaxOpenConns
+	if maxOpen <= 0 {
+		maxOpen = defaultMaxOpenConns
+	}
+
+	maxIdle := cfg.MaxIdleConns
+	if maxIdle <= 0 {
+		maxIdle = defaultMaxIdleConns
+	}
+	maxIdle = min(maxIdle, maxOpen)
+
+	lifetime := cfg.ConnMaxLifetime
+	if lifetime <= 0 {
+		lifetime = defaultConnMaxLifetime
+	}
+
+	idleTime := cfg.ConnMaxIdleTime
+	if idleTime <= 0 {
+		idleTime = defaultConnMaxIdleTime
+	}
+
+	conn.SetMaxOpenConns(maxOpen)
+	conn.SetMaxIdleConns(maxIdle)
+	conn.SetConnMaxLifetime(lifetime)
+	conn.SetConnMaxIdleTime(idleTime)
+}
+
+// Stats returns the connection pool statistics, zero until the connection is opened
+func (d *DB) Stats() sql.DBStats {
+	conn := d.Raw()
+	if conn == nil {
+		return sql.DBStats{}
+	}
+	return conn.Stats()
+}
+
+// statementContext bounds ctx by the configured statement timeout
+func (d *DB) statementContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := d.settings.Load().StatementTimeout
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// startHealthCheck pings the database every interval until stop is closed,
+// logging failures, recoveries and pool saturation
+func (d *DB) startHealthCheck(conn *sql.DB, interval time.Duration) {
+	d.stop = make(chan struct{})
+	d.wg.Add(1)
+
+	go func(stop <-chan struct{}) {
+		defer d.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		healthy := true
+		last := conn.Stats()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), min(healthPingTimeout, interval))
+			start := time.Now()
+			err := conn.PingContext(ctx)
+			cancel()
+
+			switch {
+			case err != nil && healthy:
+				healthy = false
+				d.logger.Warn("database health check failed", logger.Error(err))
+			case err != nil:
+				d.logger.Debug("database still unhealthy", logger.Error(err))
+			case !healthy:
+				healthy = true
+				d.logger.Info("database health recovered", logger.Duration("ping", time.Since(start)))
+			}
+
+			stats := conn.Stats()
+			if waits := stats.WaitCount - last.WaitCount; waits > 0 {
+				d.logger.Warn("database pool saturated",
+					logger.Int64("waits", waits),
+					logger.Duration("wait_duration", stats.WaitDuration-last.WaitDuration),
+					logger.Int("open_connections", stats.OpenConnections),
+					logger.Int("max_open_connections", stats.MaxOpenConnections),
+				)
+			}
+			last = stats
+		}
+	}(d.stop)
+}
+
+// stopHealthCheck stops the health check goroutine, if running
+func (d *DB) stopHealthCheck() {
+	if d.stop == nil {
+		return
+	}
+	close(d.stop)
+	d.wg.Wait()
+	d.stop = nil
+}
@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+)
+
+func TestApplyPool(t *testing.T) {
+	tests := []struct {
+		name             string
+		cfg              config.DBConfig
+		maxOpen, maxIdle int
+	}{
+		{"defaults", config.DBConfig{}, defaultMaxOpenConns, defaultMaxIdleConns},
+		{"configured", config.DBConfig{MaxOpenConns: 4, MaxIdleConns: 3}, 4, 3},
+		{"idle bounded by open", config.DBConfig{MaxOpenConns: 3, MaxIdleConns: 8}, 3, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := sql.OpenDB(&fakeDriver{})
+			defer conn.Close()
+
+			applyPool(conn, tt.cfg)
+
+			if got := conn.Stats().MaxOpenConnections; got != tt.maxOpen {
+				t.Fatalf("max open %d, want %d", got, tt.maxOpen)
+			}
+
+			// Idle connections beyond the limit are closed when released
+			conns := make([]*sql.Conn, tt.maxOpen)
+			for i := range conns {
+				c, err := conn.Conn(context.Background())
+				if err != nil {
+					t.Fatalf("Conn: %v", err)
+				}
+				conns[i] = c
+			}
+			for _, c := range conns {
+				c.Close()
+			}
+			if got := conn.Stats().Idle; got != tt.maxIdle {
+				t.Fatalf("idle %d, want %d", got, tt.maxIdle)
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	d, err := Open(config.DBConfig{Driver: "zrfake", Dialect: "oracle", Host: "zr", Port: 1521, Database: "ZR", MaxOpenConns: 4}, logger.NewNoOpLogger())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+
+	if stats := d.Stats(); stats != (sql.DBStats{}) {
+		t.Fatalf("stats before the connection is opened: %+v", stats)
+	}
+
+	if _, err := d.Conn(context.Background()); err != nil {
+		t.Fatalf("Conn: %v", err)
+	}
+	if got := d.Stats().MaxOpenConnections; got != 4 {
+		t.Fatalf("max open %d, want 4", got)
+	}
+}
+
+func TestStatementContext(t *testing.T) {
+	d, _ := newFakeDB(t, DialectOracle, config.DBConfig{}, nil)
+
+	ctx, cancel := d.statementContext(context.Background())
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Fatal("deadline set without a statement timeout")
+	}
+
+	d.SetConfig(config.DBConfig{StatementTimeout: time.Minute})
+
+	ctx, cancel = d.statementContext(context.Background())
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > time.Minute {
+		t.Fatalf("deadline %v, ok %v", deadline, ok)
+	}
+}
+
+func TestStatementTimeoutAppliesInTransactions(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{StatementTimeout: time.Minute}, func(query string, args []driver.NamedValue) fakeResult {
+		return fakeResult{cols: []string{"N"}, rows: [][]driver.Value{{int64(1)}}}
+	})
+	ctx := context.Background()
+
+	err := d.WithTx(ctx, nil, func(tx Tx) error {
+		if _, err := tx.ExecContext(ctx, "UPDATE CONTRACT SET MEMO = 'x'"); err != nil {
+			return err
+		}
+		rows, err := tx.QueryContext(ctx, "SELECT 1 FROM DUAL")
+		if err != nil {
+			return err
+		}
+		rows.Close()
+		var n int
+		return tx.QueryRowContext(ctx, "SELECT 1 FROM DUAL").Scan(&n)
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+	if got := drv.deadlines.Load(); got != 3 {
+		t.Fatalf("%d of 3 statements ran with the statement timeout", got)
+	}
+}
+
+func TestHealthCheckLogsDegradationAndRecovery(t *testing.T) {
+	log := &recordLogger{}
+	d, err := Open(config.DBConfig{Driver: "zrfake", Dialect: "oracle", Host: "zr", Port: 1521, Database: "ZR", HealthCheckInterval: 5 * time.Millisecond}, log)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	if _, err := d.Conn(context.Background()); err != nil {
+		t.Fatalf("Conn: %v", err)
+	}
+
+	connectDriver.failPing.Store(true)
+	t.Cleanup(func() { connectDriver.failPing.Store(false) })
+	waitFor(t, func() bool { return len(log.find("database health check failed")) > 0 })
+
+	connectDriver.failPing.Store(false)
+	waitFor(t, func() bool { return len(log.find("database health recovered")) > 0 })
+
+	if err := d.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if n := len(log.find("database health check failed")); n != 1 {
+		t.Fatalf("degradation logged %d times, want once", n)
+	}
+}
+
+// waitFor polls cond until it holds or a second has passed
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+	"strings"
+	"time"
+)
+
+// selectQuery builds a SELECT with bound conditions, ordering and paging for a dialect
+type selectQuery struct {
+	dialect Dialect
+	table   string
+	columns []string
+	where   []string
+	args    []any
+	orderBy []string
+	limit   int
+	offset  int
+}
+
+func newSelect(dialect Dialect, table string, columns []string) *selectQuery {
+	return &selectQuery{dialect: dialect, table: table, columns: columns}
+}
+
+// Where adds a condition. Every %s in cond is replaced by the placeholder of the next arg.
+func (q *selectQuery) Where(cond string, args ...any) *selectQuery {
+	placeholders := make([]any, len(args))
+	for i := range args {
+		placeholders[i] = q.dialect.Placeholder(len(q.args) + i + 1)
+	}
+
+	q.where = append(q.where, fmt.Sprintf(cond, placeholders...))
+	q.args = append(q.args, args...)
+	return q
+}
+
+// OrderBy appends a sort column
+func (q *selectQuery) OrderBy(column string, desc bool) *selectQuery {
+	if desc {
+		column += " DESC"
+	}
+	q.orderBy = append(q.orderBy, column)
+	return q
+}
+
+// Page limits the result, 0 = no limit
+func (q *selectQuery) Page(limit, offset int) *selectQuery {
+	q.limit = max(limit, 0)
+	q.offset = max(offset, 0)
+	return q
+}
+
+// String returns the query text
+func (q *selectQuery) String() string {
+	var b strings.Builder
+	b.WriteString(selectFrom(q.table, q.columns))
+
+	if len(q.where) > 0 {
+		b.WriteString(" WHERE ")
+		b.WriteString(strings.Join(q.where, " AND "))
+	}
+
+	if len(q.orderBy) > 0 {
+		b.WriteString(" ORDER BY ")
+		b.WriteString(strings.Join(q.orderBy, ", "))
+	}
+
+	switch {
+	case q.limit == 0 && q.offset == 0:
+	case q.dialect == DialectOracle:
+		// Oracle 12c+ row limiting clause
+		if q.offset > 0 {
+			fmt.Fprintf(&b, " OFFSET %d ROWS", q.offset)
+		}
+		if q.limit > 0 {
+			fmt.Fprintf(&b, " FETCH NEXT %d ROWS ONLY", q.limit)
+		}
+	default:
+		if q.limit > 0 {
+			fmt.Fprintf(&b, " LIMIT %d", q.limit)
+		}
+		if q.offset > 0 {
+			fmt.Fprintf(&b, " OFFSET %d", q.offset)
+		}
+	}
+
+	return b.String()
+}
+
+// Args returns the bound values, in placeholder order
+func (q *selectQuery) Args() []any {
+	return q.args
+}
+
+// likeEscape is the escape character of LIKE patterns built by likePrefix
+const likeEscape = `\`
+
+// likePrefix returns a LIKE pattern matching values starting with prefix
+func likePrefix(prefix string) string {
+	return escapeLike(prefix) + "%"
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_").Replace(value)
+}
+
+// dayStart drops the time of day, date columns are compared by calendar day
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// streamRows runs query and yields one mapped value per row. Rows are read as the sequence
+// is consumed and closed when the consumer stops, so large tables are never held in memory.
+func streamRows[T any](ctx context.Context, d *DB, q Querier, query string, args []any, scan func(*sql.Rows) (T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		// Releases the statement context as soon as the consumer stops
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			value, err := scan(rows)
+			if err != nil {
+				yield(zero, wrapError("failed to read row", query, err))
+				return
+			}
+			if !yield(value, nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			yield(zero, wrapError("failed to read rows", query, err))
+		}
+	}
+}
+
+// collect reads a sequence into a slice, stopping at the first error
+func collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	items := []T{}
+	for item, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,268 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+)
+
+// defaultSlowQueryThreshold is used when the configuration leaves SlowQueryThreshold at zero
+const defaultSlowQueryThreshold = time.Second
+
+// maskedValue replaces the bound values of masked columns in logs
+const maskedValue = "***"
+
+// defaultMaskedColumns are masked when the configuration sets no MaskedColumns
+var defaultMaskedColumns = []string{"PASSWORD", "PIN", "CARDNO", "IDNO", "LPN1", "LPN2", "LPN3"}
+
+// QueryStats describes a statement run through the SDK
+type QueryStats struct {
+	Operation    string        // SELECT, INSERT, ...
+	Query        string        // Parameterized, sanitized query text
+	Duration     time.Duration // Time until the driver returned
+	RowsAffected int64         // -1 for queries
+	Slow         bool          // Duration above the slow query threshold
+	Err          error
+}
+
+// MetricsHook receives the stats of every statement run through the SDK.
+// It is called synchronously and must not block.
+type MetricsHook func(QueryStats)
+
+// SetMetricsHook sets the hook receiving query stats, nil removes it
+func (d *DB) SetMetricsHook(hook MetricsHook) {
+	if d == nil {
+		return
+	}
+	if hook == nil {
+		d.metrics.Store(nil)
+		return
+	}
+	d.metrics.Store(&hook)
+}
+
+// loggedQuerier logs and measures the statements run on q, bounds them by the statement timeout
+// and wraps driver errors
+type loggedQuerier struct {
+	q  Querier
+	db *DB
+}
+
+func (l loggedQuerier) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	// Row returning writes (INSERT ... RETURNING) are refused like ExecContext
+	if l.db.readOnly.Load() && isWriteStatement(query) {
+		return nil, l.db.errReadOnly(query)
+	}
+
+	ctx, cancel := l.db.statementContext(ctx)
+
+	start := time.Now()
+	rows, err := l.q.QueryContext(ctx, query, args...)
+	l.db.observe(query, args, time.Since(start), -1, err)
+
+	// The timeout also bounds reading the rows: on success, the context is released by its timer
+	// or when the caller's context is done
+	if err != nil {
+		cancel()
+		return nil, wrapError("query failed", query, err)
+	}
+	return rows, nil
+}
+
+// QueryRowContext cannot refuse a statement (*sql.Row carries no error of its own): in degraded
+// mode, transactions are read-only so the database refuses writes run through it.
+func (l loggedQuerier) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, cancel := l.db.statementContext(ctx)
+
+	start := time.Now()
+	row := l.q.QueryRowContext(ctx, query, args...)
+	l.db.observe(query, args, time.Since(start), -1, row.Err())
+
+	// Released by its timer once the row is scanned, like the rows of QueryContext
+	if row.Err() != nil {
+		cancel()
+	}
+	return row
+}
+
+func (l loggedQuerier) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if l.db.readOnly.Load() {
+		return nil, l.db.errReadOnly(query)
+	}
+
+	ctx, cancel := l.db.statementContext(ctx)
+	defer cancel()
+
+	start := time.Now()
+	result, err := l.q.ExecContext(ctx, query, args...)
+
+	affected := int64(-1)
+	if err == nil {
+		if n, rowsErr := result.RowsAffected(); rowsErr == nil {
+			affected = n
+		}
+	}
+	l.db.observe(query, args, time.Since(start), affected, err)
+
+	if err != nil {
+		return nil, wrapError("statement failed", query, err)
+	}
+	return result, nil
+}
+
+// isWriteStatement reports whether query may modify data, anything but SELECT and WITH
+func isWriteStatement(query string) bool {
+	switch operationOf(query) {
+	case "SELECT", "WITH":
+		return false
+	default:
+		return true
+	}
+}
+
+// observe logs a statement and passes its stats to the metrics hook
+func (d *DB) observe(query string, args []any, duration time.Duration, affected int64, err error) {
+	threshold := d.settings.Load().SlowQueryThreshold
+	if threshold <= 0 {
+		threshold = defaultSlowQueryThreshold
+	}
+
+	stats := QueryStats{
+		Operation:    operationOf(query),
+		Query:        SanitizeQuery(query),
+		Duration:     duration,
+		RowsAffected: affected,
+		Slow:         duration > threshold,
+		Err:          err,
+	}
+
+	// Bound values are only formatted when the statement is logged
+	if stats.Slow || logger.Enabled(d.logger, logger.LevelDebug) {
+		fields := []logger.Field{
+			logger.String("operation", stats.Operation),
+			logger.String("query", stats.Query),
+			logger.Duration("duration", duration),
+			logger.Int64("rows_affected", affected),
+		}
+		if len(args) > 0 {
+			fields = append(fields, logger.Any("args", d.maskArgs(query, args)))
+		}
+		if err != nil {
+			fields = append(fields, logger.Error(err))
+		}
+
+		if stats.Slow {
+			d.logger.Warn("slow database query", append(fields, logger.Duration("threshold", threshold))...)
+		} else {
+			d.logger.Debug("database query", fields...)
+		}
+	}
+
+	if hook := d.metrics.Load(); hook != nil {
+		(*hook)(stats)
+	}
+}
+
+// maskArgs formats the bound values of a query, masking those bound to masked columns.
+// Masking fails closed: a value that cannot be attributed to a column, e.g. compared to
+// UPPER(CARDNO), is masked as well. An empty (non-nil) MaskedColumns disables masking.
+func (d *DB) maskArgs(query string, args []any) []string {
+	masked := d.settings.Load().MaskedColumns
+	if masked == nil {
+		masked = defaultMaskedColumns
+	}
+
+	columns := paramColumns(query, len(args))
+
+	values := make([]string, len(args))
+	for i, arg := range args {
+		if len(masked) > 0 && isMaskedColumn(columns[i], masked) {
+			values[i] = maskedValue
+			continue
+		}
+		values[i] = formatArg(arg)
+	}
+	return values
+}
+
+// isMaskedColumn reports whether values bound to column must be masked, an unknown ("") column always is
+func isMaskedColumn(column string, masked []string) bool {
+	if column == "" {
+		return true
+	}
+	for _, m := range masked {
+		if strings.EqualFold(column, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatArg(arg any) string {
+	switch v := arg.(type) {
+	case nil:
+		return "NULL"
+	case sql.NamedArg:
+		return v.Name + "=" + formatArg(v.Value)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case []byte:
+		return "<" + strconv.Itoa(len(v)) + " bytes>"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+var (
+	placeholder   = regexp.MustCompile(`:\d+|\$\d+|\?`)
+	comparedParam = regexp.MustCompile(`(?i)(?:^|\b(?:WHERE|AND|OR|NOT|ON|SET)\s+|[(,]\s*)([\w.]+)\s*(?:=|<>|!=|<=|>=|<|>|\bLIKE|\bIN|\bNOT\s+LIKE|\bNOT\s+IN)\s*\(?\s*(?:[^()]*,\s*)?$`)
+	insertColumns = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s+[\w.]+\s*\(([^)]*)\)\s*VALUES\s*\(([^)]*)\)`)
+)
+
+// paramColumns guesses the column each bound parameter is compared to or inserted into,
+// empty when unknown. Only a bare column directly compared to the parameter is attributed:
+// expressions such as UPPER(CARDNO) = :1 or A || B = :1 leave it unknown. Indexes follow args: ":2" / "$2" is args[1], "?" count in order.
+func paramColumns(query string, count int) []string {
+	columns := make([]string, count)
+
+	set := func(ref string, ordinal int, column string) {
+		index := ordinal
+		if ref != "?" {
+			n, err := strconv.Atoi(ref[1:])
+			if err != nil {
+				return
+			}
+			index = n - 1
+		}
+		if index >= 0 && index < count && columns[index] == "" {
+			columns[index] = strings.ToUpper(column[strings.LastIndex(column, ".")+1:])
+		}
+	}
+
+	// INSERT INTO t (a, b) VALUES (:1, :2)
+	if m := insertColumns.FindStringSubmatch(query); m != nil {
+		names := strings.Split(m[1], ",")
+		values := strings.Split(m[2], ",")
+		for i := 0; i < len(names) && i < len(values); i++ {
+			value := strings.TrimSpace(values[i])
+			if placeholder.MatchString(value) && placeholder.FindString(value) == value {
+				set(value, i, strings.TrimSpace(names[i]))
+			}
+		}
+	}
+
+	// column = :1, column IN (:1, :2), column LIKE ?
+	for ordinal, loc := range placeholder.FindAllStringIndex(query, -1) {
+		if m := comparedParam.FindStringSubmatch(query[:loc[0]]); m != nil {
+			set(query[loc[0]:loc[1]], ordinal, m[1])
+		}
+	}
+
+	return columns
+}
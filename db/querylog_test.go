@@ -0,0 +1,97 @@
+package db
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+)
+
+func TestParamColumns(t *testing.T) {
+	tests := []struct {
+		query string
+		count int
+		want  string
+	}{
+		{"SELECT * FROM CONSUMER WHERE CARDNO = :1 AND NAME LIKE :2", 2, "[CARDNO NAME]"},
+		{"SELECT * FROM CONSUMER WHERE C.CARDNO IN (:1, :2) AND NAME <> $3", 3, "[CARDNO CARDNO NAME]"},
+		{"SELECT * FROM CONSUMER WHERE (VALIDFROM IS NULL OR VALIDFROM < ?)", 1, "[VALIDFROM]"},
+		{"INSERT INTO CONSUMER (NAME, CARDNO) VALUES (:1, :2)", 2, "[NAME CARDNO]"},
+		{"UPDATE CONSUMER SET MEMO = :1 WHERE CARDNO NOT IN (:2)", 2, "[MEMO CARDNO]"},
+
+		// Expressions cannot be attributed to a single column
+		{"SELECT * FROM CONSUMER WHERE UPPER(CARDNO) = :1", 1, "[]"},
+		{"SELECT * FROM CONSUMER WHERE NAME || CARDNO = :1", 1, "[]"},
+		{"SELECT * FROM CONSUMER WHERE NAME = UPPER(:1)", 1, "[]"},
+		{"SELECT * FROM CONSUMER WHERE CARDNO BETWEEN :1 AND :2", 2, "[ ]"},
+	}
+
+	for _, tt := range tests {
+		if got := fmt.Sprint(paramColumns(tt.query, tt.count)); got != tt.want {
+			t.Errorf("paramColumns(%q) = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestMaskArgsFailsClosed(t *testing.T) {
+	d, _ := newFakeDB(t, DialectOracle, config.DBConfig{}, nil)
+
+	tests := []struct {
+		query string
+		args  []any
+		want  string
+	}{
+		{"SELECT * FROM CONSUMER WHERE CARDNO = :1 AND NAME = :2", []any{"111", "Bob"}, "[*** Bob]"},
+		{"SELECT * FROM CONSUMER WHERE UPPER(CARDNO) = :1 AND CONTRACTID = :2", []any{"111", 9}, "[*** 9]"},
+		{"SELECT * FROM CONSUMER WHERE CARDNO IN (:1,:2)", []any{"111", "222"}, "[*** ***]"},
+		{"SELECT * FROM CONSUMER WHERE REPLACE(UPPER(LPN1), ' ', '') = :1", []any{"AB123"}, "[***]"},
+	}
+
+	for _, tt := range tests {
+		if got := fmt.Sprint(d.maskArgs(tt.query, tt.args)); got != tt.want {
+			t.Errorf("maskArgs(%q) = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+
+	// An empty list disables masking
+	d.SetConfig(config.DBConfig{MaskedColumns: []string{}})
+	if got := fmt.Sprint(d.maskArgs("SELECT * FROM CONSUMER WHERE UPPER(CARDNO) = :1", []any{"111"})); got != "[111]" {
+		t.Errorf("masking not disabled: %s", got)
+	}
+}
+
+// argCounter counts how often its value is formatted
+type argCounter struct{ n *int }
+
+func (a argCounter) String() string {
+	*a.n++
+	return "value"
+}
+
+func TestObserveSkipsArgsWhenDebugDisabled(t *testing.T) {
+	out := &recordLogger{}
+	log := logger.NewDefaultLogger(logger.DefaultLoggerOptions{Level: logger.LevelInfo, Output: io.Discard})
+
+	d, _ := newFakeDB(t, DialectOracle, config.DBConfig{}, nil)
+	d.logger = log
+
+	formatted := 0
+	d.observe("UPDATE CONTRACT SET NAME = :1", []any{argCounter{&formatted}}, 0, 1, nil)
+	if formatted != 0 {
+		t.Fatalf("arguments formatted %d times with debug disabled", formatted)
+	}
+
+	log.SetLevel(logger.LevelDebug)
+	d.observe("UPDATE CONTRACT SET NAME = :1", []any{argCounter{&formatted}}, 0, 1, nil)
+	if formatted != 1 {
+		t.Fatalf("arguments formatted %d times with debug enabled", formatted)
+	}
+
+	d.logger = out
+	d.observe("UPDATE CONTRACT SET NAME = :1", []any{"Bob"}, 0, 1, nil)
+	if entries := out.find("database query"); len(entries) != 1 {
+		t.Fatalf("%d entries logged by a logger without level", len(entries))
+	}
+}
@@ -0,0 +1,472 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// ZR schema version table, one row per installed version or upgrade
+const (
+	versionTable  = "DBVERSION"
+	versionColumn = "VERSION"
+)
+
+// ZR contract table and columns
+const (
+	contractTable         = "CONTRACT"
+	contractColID         = "CONTRACTID"
+	contractColName       = "NAME"
+	contractColValidFrom  = "VALIDFROM"
+	contractColValidUntil = "VALIDUNTIL"
+	contractColFilialID   = "FILIALID"
+	contractColStatus     = "STATUS"
+	contractColDeleted    = "DELETED"
+	contractColMemo       = "MEMO"
+	contractColCounting   = "COUNTING"
+	contractColPresent    = "PRESENT"
+	contractColCreated    = "CREATED"      // Creation timestamp, read by change feeds
+	contractColModified   = "LASTMODIFIED" // Modification timestamp, read by change feeds
+)
+
+// contractColumns is the column list read for a contract, in scan order
+var contractColumns = []string{
+	contractColID,
+	contractColName,
+	contractColValidFrom,
+	contractColValidUntil,
+	contractColFilialID,
+	contractColStatus,
+	contractColDeleted,
+	contractColMemo,
+	contractColCounting,
+	contractColPresent,
+}
+
+// contractSortColumns maps the sort fields of a contract filter to columns
+var contractSortColumns = map[models.ContractSortField]string{
+	models.ContractSortByID:         contractColID,
+	models.ContractSortByName:       contractColName,
+	models.ContractSortByValidFrom:  contractColValidFrom,
+	models.ContractSortByValidUntil: contractColValidUntil,
+}
+
+// contractQuery builds the query reading the contracts matching filter
+func contractQuery(dialect Dialect, filter models.ContractFilter) *selectQuery {
+	q := newSelect(dialect, contractTable, contractColumns)
+
+	if filter.NamePrefix != "" {
+		q.Where("UPPER("+contractColName+") LIKE %s ESCAPE '"+likeEscape+"'", likePrefix(strings.ToUpper(filter.NamePrefix)))
+	}
+	if filter.NameContains != "" {
+		q.Where("UPPER("+contractColName+") LIKE %s ESCAPE '"+likeEscape+"'", "%"+escapeLike(strings.ToUpper(filter.NameContains))+"%")
+	}
+	if filter.FilialID != "" {
+		q.Where(contractColFilialID+" = %s", filter.FilialID)
+	}
+	if filter.MinID > 0 {
+		q.Where(contractColID+" >= %s", filter.MinID)
+	}
+	if filter.MaxID > 0 {
+		q.Where(contractColID+" <= %s", filter.MaxID)
+	}
+	if !filter.ActiveOn.IsZero() {
+		whereValidOn(q, contractColValidFrom, contractColValidUntil, filter.ActiveOn)
+	}
+	if !filter.ExpiringBefore.IsZero() {
+		q.Where(contractColValidUntil+" < %s", dayStart(filter.ExpiringBefore))
+	}
+	whereArchived(q, contractColDeleted, filter.Archived)
+
+	if column, ok := contractSortColumns[filter.SortBy]; ok && column != contractColID {
+		q.OrderBy(column, filter.SortDesc)
+	}
+	// Always end with the key, so paging is stable
+	q.OrderBy(contractColID, filter.SortDesc && (filter.SortBy == models.ContractSortByID || filter.SortBy == ""))
+
+	return q.Page(filter.Limit, filter.Offset)
+}
+
+// contractRow holds the raw values of a contract row
+type contractRow struct {
+	ID         int
+	Name       sql.NullString
+	ValidFrom  dateValue
+	ValidUntil dateValue
+	FilialID   sql.NullString
+	Status     sql.NullInt64
+	Deleted    sql.NullInt64
+	Memo       sql.NullString
+	Counting   sql.NullInt64
+	Present    sql.NullInt64
+}
+
+// targets returns the scan destinations, in contractColumns order
+func (r *contractRow) targets() []any {
+	return []any{&r.ID, &r.Name, &r.ValidFrom, &r.ValidUntil, &r.FilialID, &r.Status, &r.Deleted, &r.Memo, &r.Counting, &r.Present}
+}
+
+// toList maps the row to a contract list entry
+func (r *contractRow) toList() models.ContractList {
+	return models.ContractList{
+		ID:         r.ID,
+		Name:       r.Name.String,
+		ValidFrom:  r.ValidFrom.String(),
+		ValidUntil: r.ValidUntil.String(),
+		FilialID:   r.FilialID.String,
+	}
+}
+
+// toDetail maps the row to a contract detail
+func (r *contractRow) toDetail() *models.ContractDetail {
+	id, status, deleted := r.ID, int(r.Status.Int64), int(r.Deleted.Int64)
+	return &models.ContractDetail{
+		Contract: models.Contract{
+			ID:         &id,
+			Name:       r.Name.String,
+			ValidFrom:  r.ValidFrom.String(),
+			ValidUntil: r.ValidUntil.String(),
+			FilialID:   r.FilialID.String,
+		},
+		Counting: int(r.Counting.Int64),
+		Present:  int(r.Present.Int64),
+		Status:   &status,
+		Delete:   &deleted,
+		Memo:     r.Memo.String,
+	}
+}
+
+// ZR participant (consumer) table and columns. Card and license plate data live on the consumer row.
+const (
+	participantTable         = "CONSUMER"
+	participantColID         = "CONSUMERID"
+	participantColContractID = "CONTRACTID"
+	participantColName       = "NAME"
+	participantColFirstName  = "FIRSTNAME"
+	participantColValidFrom  = "VALIDFROM"
+	participantColValidUntil = "VALIDUNTIL"
+	participantColFilialID   = "FILIALID"
+	participantColPtcptType  = "PTCPTTYPE"
+	participantColCardNo     = "CARDNO"
+	participantColCardClass  = "CARDCLASS"
+	participantColIdentType  = "IDENTIFICATIONTYPE"
+	participantColCardStatus = "CARDSTATUS"
+	participantColLPN1       = "LPN1"
+	participantColLPN2       = "LPN2"
+	participantColLPN3       = "LPN3"
+	participantColMemo       = "MEMO"
+	participantColPresent    = "PRESENT"
+	participantColStatus     = "STATUS"
+	participantColDeleted    = "DELETED"
+	participantColCreated    = "CREATED"      // Creation timestamp, read by change feeds
+	participantColModified   = "LASTMODIFIED" // Modification timestamp, read by change feeds
+)
+
+// participantColumns is the column list read for a participant, in scan order
+var participantColumns = []string{
+	participantColID,
+	participantColContractID,
+	participantColName,
+	participantColFirstName,
+	participantColValidFrom,
+	participantColValidUntil,
+	participantColFilialID,
+	participantColPtcptType,
+	participantColCardNo,
+	participantColCardClass,
+	participantColIdentType,
+	participantColCardStatus,
+	participantColLPN1,
+	participantColLPN2,
+	participantColLPN3,
+	participantColMemo,
+	participantColPresent,
+	participantColStatus,
+	participantColDeleted,
+}
+
+// participantQuery builds the query reading the participants matching filter
+func participantQuery(dialect Dialect, filter models.ParticipantFilter) *selectQuery {
+	q := newSelect(dialect, participantTable, participantColumns)
+
+	if filter.ContractID > 0 {
+		q.Where(participantColContractID+" = %s", filter.ContractID)
+	}
+	if filter.NamePrefix != "" {
+		q.Where("UPPER("+participantColName+") LIKE %s ESCAPE '"+likeEscape+"'", likePrefix(strings.ToUpper(filter.NamePrefix)))
+	}
+	if filter.FilialID != "" {
+		q.Where(participantColFilialID+" = %s", filter.FilialID)
+	}
+	if filter.CardNumber != "" {
+		q.Where(participantColCardNo+" = %s", filter.CardNumber)
+	}
+	if filter.LicensePlate != "" {
+		wherePlate(q, models.ParseLicensePlate(filter.LicensePlate))
+	}
+	if !filter.ActiveOn.IsZero() {
+		whereValidOn(q, participantColValidFrom, participantColValidUntil, filter.ActiveOn)
+	}
+	if filter.Present != nil {
+		q.Where(participantColPresent+" = %s", int(*filter.Present))
+	}
+	whereArchived(q, participantColDeleted, filter.Archived)
+
+	q.OrderBy(participantColContractID, false).OrderBy(participantColID, false)
+
+	return q.Page(filter.Limit, filter.Offset)
+}
+
+// wherePlate matches a plate in any slot, with the semantics of models.LicensePlate.Matches.
+// Slots are normalized like the plate, so a number stored as "AB-123" matches AB123.
+func wherePlate(q *selectQuery, plate models.LicensePlate) {
+	var conds []string
+	var args []any
+
+	for _, slot := range []string{participantColLPN1, participantColLPN2, participantColLPN3} {
+		conds = append(conds, normalizedPlate(slot)+" = %s")
+		args = append(args, plate.Number)
+	}
+
+	q.Where("("+strings.Join(conds, " OR ")+")", args...)
+}
+
+// normalizedPlate returns the SQL expression of a plate column normalized like models.NormalizePlate
+func normalizedPlate(column string) string {
+	expr := "UPPER(" + column + ")"
+	for _, c := range []string{" ", "-", ".", "\t"} {
+		expr = "REPLACE(" + expr + ", '" + c + "', '')"
+	}
+	return expr
+}
+
+// participantRow holds the raw values of a participant row
+type participantRow struct {
+	ID         int
+	ContractID int
+	Name       sql.NullString
+	FirstName  sql.NullString
+	ValidFrom  dateValue
+	ValidUntil dateValue
+	FilialID   sql.NullString
+	PtcptType  sql.NullInt64
+	CardNo     sql.NullString
+	CardClass  sql.NullInt64
+	IdentType  sql.NullInt64
+	CardStatus sql.NullInt64
+	LPN1       sql.NullString
+	LPN2       sql.NullString
+	LPN3       sql.NullString
+	Memo       sql.NullString
+	Present    sql.NullInt64
+	Status     sql.NullInt64
+	Deleted    sql.NullInt64
+}
+
+// targets returns the scan destinations, in participantColumns order
+func (r *participantRow) targets() []any {
+	return []any{
+		&r.ID, &r.ContractID, &r.Name, &r.FirstName, &r.ValidFrom, &r.ValidUntil, &r.FilialID,
+		&r.PtcptType, &r.CardNo, &r.CardClass, &r.IdentType, &r.CardStatus,
+		&r.LPN1, &r.LPN2, &r.LPN3, &r.Memo, &r.Present, &r.Status, &r.Deleted,
+	}
+}
+
+// toList maps the row to a participant list entry
+func (r *participantRow) toList() models.ParticipantList {
+	return models.ParticipantList{
+		ID:         r.ID,
+		ContractID: r.ContractID,
+		Name:       r.Name.String,
+		FirstName:  r.FirstName.String,
+		ValidFrom:  r.ValidFrom.String(),
+		ValidUntil: r.ValidUntil.String(),
+		FilialID:   r.FilialID.String,
+	}
+}
+
+// toDetail maps the row to a participant detail. Identification is only set when a card is assigned.
+func (r *participantRow) toDetail() *models.ParticipantDetail {
+	id := r.ID
+	detail := &models.ParticipantDetail{
+		Participant: models.Participant{
+			ID:         &id,
+			ContractID: r.ContractID,
+			Name:       r.Name.String,
+			FirstName:  r.FirstName.String,
+			ValidFrom:  r.ValidFrom.String(),
+			ValidUntil: r.ValidUntil.String(),
+			FilialID:   r.FilialID.String,
+		},
+		LPN1:    r.LPN1.String,
+		LPN2:    r.LPN2.String,
+		LPN3:    r.LPN3.String,
+		Memo:    r.Memo.String,
+		Present: models.PresenceState(r.Present.Int64),
+		Status:  int(r.Status.Int64),
+		Delete:  int(r.Deleted.Int64),
+	}
+
+	if r.CardNo.String != "" || r.CardClass.Int64 != 0 {
+		detail.Identification = &models.Identification{
+			ParticipantType:    int(r.PtcptType.Int64),
+			CardNo:             r.CardNo.String,
+			CardClass:          int(r.CardClass.Int64),
+			IdentificationType: int(r.IdentType.Int64),
+			ValidFrom:          r.ValidFrom.String(),
+			ValidUntil:         r.ValidUntil.String(),
+			Status:             int(r.CardStatus.Int64),
+		}
+	}
+
+	return detail
+}
+
+// whereValidOn keeps rows valid on day, a NULL bound is open
+func whereValidOn(q *selectQuery, fromColumn, untilColumn string, day time.Time) {
+	day = dayStart(day)
+	q.Where("("+fromColumn+" IS NULL OR "+fromColumn+" < %s)", day.AddDate(0, 0, 1))
+	q.Where("("+untilColumn+" IS NULL OR "+untilColumn+" >= %s)", day)
+}
+
+// whereArchived applies an archive filter on the soft delete column
+func whereArchived(q *selectQuery, deletedColumn string, filter models.ArchiveFilter) {
+	switch filter {
+	case models.ArchivedExclude:
+		q.Where("(" + deletedColumn + " IS NULL OR " + deletedColumn + " = 0)")
+	case models.ArchivedOnly:
+		q.Where(deletedColumn + " <> 0")
+	}
+}
+
+// changeSource describes how a change feed reads an entity table
+type changeSource struct {
+	entity      string
+	table       string
+	idColumn    string
+	columns     []string // Entity columns followed by the creation and modification columns
+	modifiedCol string
+	scan        func(rows *sql.Rows) (changeRow, error)
+}
+
+// changeRow is a row read by a change feed
+type changeRow struct {
+	event    models.ChangeEvent
+	created  dateValue
+	modified dateValue
+	deleted  bool
+}
+
+// contractChanges reads contract changes
+var contractChanges = changeSource{
+	entity:      "contract",
+	table:       contractTable,
+	idColumn:    contractColID,
+	columns:     append(append([]string{}, contractColumns...), contractColCreated, contractColModified),
+	modifiedCol: contractColModified,
+	scan: func(rows *sql.Rows) (changeRow, error) {
+		var row contractRow
+		var change changeRow
+		if err := rows.Scan(append(row.targets(), &change.created, &change.modified)...); err != nil {
+			return changeRow{}, err
+		}
+		change.event = models.ChangeEvent{Entity: "contract", ID: row.ID, ContractID: row.ID, Contract: row.toDetail()}
+		change.deleted = row.Deleted.Int64 != 0
+		return change, nil
+	},
+}
+
+// participantChanges reads participant changes
+var participantChanges = changeSource{
+	entity:      "participant",
+	table:       participantTable,
+	idColumn:    participantColID,
+	columns:     append(append([]string{}, participantColumns...), participantColCreated, participantColModified),
+	modifiedCol: participantColModified,
+	scan: func(rows *sql.Rows) (changeRow, error) {
+		var row participantRow
+		var change changeRow
+		if err := rows.Scan(append(row.targets(), &change.created, &change.modified)...); err != nil {
+			return changeRow{}, err
+		}
+		change.event = models.ChangeEvent{Entity: "participant", ID: row.ID, ContractID: row.ContractID, Participant: row.toDetail()}
+		change.deleted = row.Deleted.Int64 != 0
+		return change, nil
+	},
+}
+
+// changeQuery builds the query reading the next batch of changes after (since, afterID).
+// The modification column is compared bare so that its index is used: rows where it is
+// NULL never match and are read by unmodifiedQuery.
+func (c changeSource) changeQuery(dialect Dialect, since time.Time, afterID, limit int) *selectQuery {
+	q := newSelect(dialect, c.table, c.columns)
+	q.Where("("+c.modifiedCol+" > %s OR ("+c.modifiedCol+" = %s AND "+c.idColumn+" > %s))", since, since, afterID)
+	return q.OrderBy(c.modifiedCol, false).OrderBy(c.idColumn, false).Page(limit, 0)
+}
+
+// unmodifiedQuery builds the query reading the next batch of rows without a modification
+// time (NULL modification column) after afterID, keyed on the ID
+func (c changeSource) unmodifiedQuery(dialect Dialect, afterID, limit int) *selectQuery {
+	q := newSelect(dialect, c.table, c.columns)
+	q.Where(c.modifiedCol + " IS NULL")
+	q.Where(c.idColumn+" > %s", afterID)
+	return q.OrderBy(c.idColumn, false).Page(limit, 0)
+}
+
+// selectFrom builds "SELECT columns FROM table"
+func selectFrom(table string, columns []string) string {
+	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ", "), table)
+}
+
+// dateValue scans DATE columns returned as time.Time, string or []byte into a ZR date
+type dateValue struct {
+	Time  time.Time
+	Valid bool
+}
+
+// Scan implements sql.Scanner
+func (d *dateValue) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*d = dateValue{}
+	case time.Time:
+		*d = dateValue{Time: v, Valid: true}
+	case string:
+		return d.parse(v)
+	case []byte:
+		return d.parse(string(v))
+	default:
+		return fmt.Errorf("unsupported date value %T", src)
+	}
+	return nil
+}
+
+func (d *dateValue) parse(value string) error {
+	if value == "" {
+		*d = dateValue{}
+		return nil
+	}
+
+	t, err := models.ParseDate(value)
+	if err != nil {
+		// Drivers returning "2006-01-02 15:04:05"
+		t, err = time.Parse(time.DateTime, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	*d = dateValue{Time: t, Valid: true}
+	return nil
+}
+
+// String formats the value as a ZR date, empty when NULL
+func (d dateValue) String() string {
+	if !d.Valid {
+		return ""
+	}
+	return models.FormatDate(d.Time)
+}
@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+)
+
+// secretConnector opens connections with a password resolved from a secret reference
+// (DBConfig.Password "env:", "file:" or "cmd:"). When the database rejects the password,
+// the reference is resolved again so that rotated credentials are picked up by new connections.
+// Connections rejected at the same time share a single re-resolution.
+type secretConnector struct {
+	db     *DB
+	driver driver.Driver
+
+	mu        sync.Mutex
+	password  string           // Resolved password, never logged
+	connector driver.Connector // Opens connections with password
+	refreshes uint64           // Completed re-resolutions
+
+	refreshMu sync.Mutex // Serializes re-resolutions
+}
+
+// newSecretConnector prepares a connector for the driver of the configuration and a resolved password
+func (d *DB) newSecretConnector(password string) (*secretConnector, error) {
+	dsn, err := d.dsn(password)
+	if err != nil {
+		return nil, err
+	}
+
+	// sql.Open does not connect, it only looks up the registered driver
+	probe, err := sql.Open(d.cfg.DriverName(), dsn)
+	if err != nil {
+		return nil, errors.NewDatabaseError("failed to open database, is driver \""+d.cfg.DriverName()+"\" imported?", "", "OPEN", nil)
+	}
+	drv := probe.Driver()
+	probe.Close()
+
+	c := &secretConnector{db: d, driver: drv}
+	if err := c.set(password); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Connect opens a connection, resolving the password again once if the database rejects it
+func (c *secretConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.mu.Lock()
+	connector, used, refreshes := c.connector, c.password, c.refreshes
+	c.mu.Unlock()
+
+	conn, err := connector.Connect(ctx)
+	if err == nil || !IsAuthError(err) {
+		return conn, err
+	}
+
+	if !c.refresh(ctx, used, refreshes) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	connector = c.connector
+	c.mu.Unlock()
+
+	return connector.Connect(ctx)
+}
+
+// Driver returns the underlying driver
+func (c *secretConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// refresh resolves the password reference again after used was rejected, refreshes being the
+// number of re-resolutions completed when the connection was attempted, and reports whether the
+// connection should be retried with a different password
+func (c *secretConnector) refresh(ctx context.Context, used string, refreshes uint64) bool {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	c.mu.Lock()
+	current, completed := c.password, c.refreshes
+	c.mu.Unlock()
+
+	switch {
+	case current != used:
+		// Already rotated by a concurrent connection
+		return true
+	case completed != refreshes:
+		// Re-resolved by a concurrent connection since the attempt, without change
+		return false
+	}
+
+	password, err := config.ResolveSecret(ctx, c.db.cfg.Password)
+	c.mu.Lock()
+	c.refreshes++
+	c.mu.Unlock()
+
+	if err != nil {
+		c.db.logger.Error("failed to re-resolve database password", logger.Error(err))
+		return false
+	}
+	if password == used {
+		return false
+	}
+
+	if err := c.set(password); err != nil {
+		c.db.logger.Error("failed to apply rotated database password", logger.Error(err))
+		return false
+	}
+
+	c.db.logger.Info("database password rotated")
+	return true
+}
+
+// set builds the connector of a resolved password
+func (c *secretConnector) set(password string) error {
+	dsn, err := c.db.dsn(password)
+	if err != nil {
+		return err
+	}
+
+	var connector driver.Connector = dsnConnector{dsn: dsn, driver: c.driver}
+	if dc, ok := c.driver.(driver.DriverContext); ok {
+		connector, err = dc.OpenConnector(dsn)
+		if err != nil {
+			// The DSN carries the password, never wrap the driver error verbatim
+			return errors.NewDatabaseError("failed to prepare database connector", "", "OPEN", nil)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.password = password
+	c.connector = connector
+	return nil
+}
+
+// dsnConnector opens connections of drivers without driver.DriverContext
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// dsn builds the data source name of the configuration with a resolved password
+func (d *DB) dsn(password string) (string, error) {
+	cfg := d.cfg
+	cfg.Password = password
+
+	dsn, err := BuildDSN(cfg)
+	if err != nil {
+		return "", errors.NewDatabaseError("failed to build connection string", "", "OPEN", err)
+	}
+	return dsn, nil
+}
@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+)
+
+// authDriver rejects connections whose DSN carries the "rejected" password.
+// First connections wait at the gate until it is closed, so they are rejected together.
+type authDriver struct {
+	mu      sync.Mutex
+	gate    chan struct{}
+	arrived chan struct{}
+	lastDSN string
+}
+
+func (d *authDriver) Open(dsn string) (driver.Conn, error) {
+	d.mu.Lock()
+	gate, arrived := d.gate, d.arrived
+	d.lastDSN = dsn
+	d.mu.Unlock()
+
+	if arrived != nil {
+		arrived <- struct{}{}
+	}
+	if gate != nil {
+		<-gate
+	}
+
+	if strings.Contains(dsn, "rejected") {
+		return nil, errors.New("ORA-01017: invalid username/password; logon denied")
+	}
+	return &fakeConn{d: &fakeDriver{}}, nil
+}
+
+var secretDriver = &authDriver{}
+
+func init() {
+	sql.Register("zrsecret", secretDriver)
+}
+
+// secretCmd writes a cmd: reference printing the content of the returned password file,
+// and counting its runs in the returned counter file
+func secretCmd(t *testing.T, password string) (ref, passwordFile, counter string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	passwordFile = filepath.Join(dir, "password")
+	counter = filepath.Join(dir, "runs")
+	script := filepath.Join(dir, "secret.sh")
+
+	writeFile(t, passwordFile, password)
+	writeFile(t, script, "#!/bin/sh\necho run >> "+counter+"\ncat "+passwordFile+"\n")
+	if err := os.Chmod(script, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	return config.SecretCmdPrefix + script, passwordFile, counter
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func runs(t *testing.T, counter string) int {
+	t.Helper()
+	data, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "run")
+}
+
+// connectTogether opens n connections whose first attempts are rejected at the same time
+func connectTogether(t *testing.T, c *secretConnector, n int) []error {
+	t.Helper()
+
+	secretDriver.mu.Lock()
+	secretDriver.gate = make(chan struct{})
+	secretDriver.arrived = make(chan struct{}, n)
+	gate := secretDriver.gate
+	secretDriver.mu.Unlock()
+
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, err := c.Connect(context.Background())
+			if err == nil {
+				conn.Close()
+			}
+			errs[i] = err
+		}()
+	}
+
+	for range n {
+		select {
+		case <-secretDriver.arrived:
+		case <-time.After(5 * time.Second):
+			t.Fatal("connections did not reach the driver")
+		}
+	}
+
+	secretDriver.mu.Lock()
+	secretDriver.gate, secretDriver.arrived = nil, nil
+	secretDriver.mu.Unlock()
+	close(gate)
+
+	wg.Wait()
+	return errs
+}
+
+func newSecretDB(t *testing.T, ref string) *secretConnector {
+	t.Helper()
+
+	d, err := Open(config.DBConfig{Driver: "zrsecret", Dialect: "oracle", Host: "zr", Port: 1521, Database: "ZR", Username: "zr", Password: ref}, logger.NewNoOpLogger())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	c, err := d.newSecretConnector(d.password)
+	if err != nil {
+		t.Fatalf("newSecretConnector: %v", err)
+	}
+	return c
+}
+
+func TestSecretConnectorSharesRotation(t *testing.T) {
+	ref, passwordFile, counter := secretCmd(t, "rejected")
+	c := newSecretDB(t, ref)
+
+	writeFile(t, passwordFile, "accepted")
+
+	for i, err := range connectTogether(t, c, 4) {
+		if err != nil {
+			t.Fatalf("connection %d: %v", i, err)
+		}
+	}
+
+	// Resolved by Open, then once for all the rejected connections
+	if n := runs(t, counter); n != 2 {
+		t.Fatalf("command run %d times, want 2", n)
+	}
+}
+
+func TestSecretConnectorSharesUnchangedResolution(t *testing.T) {
+	ref, _, counter := secretCmd(t, "rejected")
+	c := newSecretDB(t, ref)
+
+	for i, err := range connectTogether(t, c, 4) {
+		if !IsAuthError(err) {
+			t.Fatalf("connection %d: got %v, want the authentication error", i, err)
+		}
+	}
+
+	if n := runs(t, counter); n != 2 {
+		t.Fatalf("command run %d times, want 2", n)
+	}
+}
+
+func TestOpenPlainPasswordEscape(t *testing.T) {
+	d, err := Open(config.DBConfig{Driver: "zrsecret", Dialect: "oracle", Host: "zr", Port: 1521, Database: "ZR", Username: "zr", Password: "plain:cmd:accepted"}, logger.NewNoOpLogger())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+
+	if _, err := d.Conn(context.Background()); err != nil {
+		t.Fatalf("Conn: %v", err)
+	}
+
+	secretDriver.mu.Lock()
+	dsn := secretDriver.lastDSN
+	secretDriver.mu.Unlock()
+	if !strings.Contains(dsn, "//zr:cmd%3Aaccepted@") {
+		t.Fatalf("escaped password not used literally: %q", dsn)
+	}
+}
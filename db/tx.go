@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+)
+
+// Transaction retry settings for deadlocks and serialization failures
+const (
+	maxTxAttempts  = 3
+	txRetryBackoff = 50 * time.Millisecond
+)
+
+// Tx is the transaction handle passed to WithTx.
+// Queries and repositories obtained from it run inside the transaction.
+// In degraded mode the transaction is read-only and write statements are refused.
+type Tx interface {
+	Querier
+
+	// Contracts returns the contract repository bound to the transaction
+	Contracts() *ContractRepository
+
+	// Participants returns the participant repository bound to the transaction
+	Participants() *ParticipantRepository
+}
+
+// dbTx implements Tx on top of *sql.Tx, statements are logged and errors wrapped
+type dbTx struct {
+	loggedQuerier
+}
+
+func (t *dbTx) Contracts() *ContractRepository {
+	return &ContractRepository{db: t.db, q: t}
+}
+
+func (t *dbTx) Participants() *ParticipantRepository {
+	return &ParticipantRepository{db: t.db, q: t}
+}
+
+// WithTx runs fn in a transaction, committed when fn returns nil and rolled back otherwise.
+// A panic in fn rolls back and is returned as an error.
+// Deadlocks and serialization failures (ORA-00060, ORA-08177, SQLSTATE 40001/40P01) retry the
+// whole transaction, so fn must not have side effects outside of tx.
+//
+// Use opts to get consistent multi-table reads, e.g. &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}.
+func (d *DB) WithTx(ctx context.Context, opts *sql.TxOptions, fn func(tx Tx) error) error {
+	for attempt := 1; ; attempt++ {
+		err := d.runTx(ctx, opts, fn)
+		if err == nil {
+			return nil
+		}
+
+		if !IsRetryableTxError(err) || attempt >= maxTxAttempts {
+			return err
+		}
+
+		backoff := txRetryBackoff*time.Duration(attempt) + rand.N(txRetryBackoff)
+		d.logger.Warn("retrying transaction after conflict",
+			logger.Int("attempt", attempt),
+			logger.Duration("backoff", backoff),
+			logger.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return errors.NewDatabaseError("transaction retry canceled", "", "BEGIN", ctx.Err())
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// runTx runs a single transaction attempt
+func (d *DB) runTx(ctx context.Context, opts *sql.TxOptions, fn func(tx Tx) error) (err error) {
+	conn, err := d.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	if d.readOnly.Load() && (opts == nil || !opts.ReadOnly) {
+		// Degraded mode: let the database refuse writes as well
+		readOnly := sql.TxOptions{ReadOnly: true}
+		if opts != nil {
+			readOnly.Isolation = opts.Isolation
+		}
+		opts = &readOnly
+	}
+
+	sqlTx, err := conn.BeginTx(ctx, opts)
+	if err != nil {
+		return wrapError("failed to begin transaction", "BEGIN", err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if rbErr := sqlTx.Rollback(); rbErr != nil {
+				d.logger.Error("failed to roll back transaction", logger.Error(rbErr))
+			}
+			d.logger.Error("transaction panicked, rolled back", logger.Any("panic", r))
+			err = errors.NewDatabaseError(fmt.Sprintf("transaction panicked: %v", r), "", "ROLLBACK", nil)
+		}
+	}()
+
+	if err := fn(&dbTx{loggedQuerier{q: sqlTx, db: d}}); err != nil {
+		if rbErr := sqlTx.Rollback(); rbErr != nil {
+			d.logger.Error("failed to roll back transaction", logger.Error(rbErr))
+		}
+		return err
+	}
+
+	if err := sqlTx.Commit(); err != nil {
+		return wrapError("failed to commit transaction", "COMMIT", err)
+	}
+
+	return nil
+}
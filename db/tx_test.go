@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+)
+
+// pgError reports a SQLSTATE like pgconn.PgError
+type pgError struct{ code string }
+
+func (e *pgError) Error() string    { return "pg error" }
+func (e *pgError) SQLState() string { return e.code }
+
+func TestWithTxCommits(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		return fakeResult{cols: []string{contractColID}, rows: [][]driver.Value{{int64(3)}}}
+	})
+
+	err := d.WithTx(context.Background(), nil, func(tx Tx) error {
+		ids, ok, err := tx.Contracts().FindByReference(context.Background(), "memo", "crm-1")
+		if err != nil || !ok || len(ids) != 1 {
+			return fmt.Errorf("FindByReference: %v, %v, %v", ids, ok, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+
+	statements := drv.recorded()
+	if len(statements) != 3 || statements[0] != "BEGIN" || statements[2] != "COMMIT" {
+		t.Fatalf("unexpected statements %q", statements)
+	}
+}
+
+func TestWithTxRollsBackOnError(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{}, nil)
+	want := stderrors.New("boom")
+
+	err := d.WithTx(context.Background(), nil, func(tx Tx) error { return want })
+	if err != want {
+		t.Fatalf("got %v, want %v", err, want)
+	}
+
+	if statements := drv.recorded(); fmt.Sprint(statements) != "[BEGIN ROLLBACK]" {
+		t.Fatalf("unexpected statements %q", statements)
+	}
+}
+
+func TestWithTxRecoversPanic(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{}, nil)
+
+	err := d.WithTx(context.Background(), nil, func(tx Tx) error { panic("broken invariant") })
+
+	var dbErr *errors.DatabaseError
+	if !stderrors.As(err, &dbErr) || dbErr.Operation != "ROLLBACK" || !strings.Contains(dbErr.Message, "broken invariant") {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if statements := drv.recorded(); fmt.Sprint(statements) != "[BEGIN ROLLBACK]" {
+		t.Fatalf("unexpected statements %q", statements)
+	}
+}
+
+func TestWithTxRetriesConflicts(t *testing.T) {
+	var calls atomic.Int32
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		if calls.Add(1) < maxTxAttempts {
+			return fakeResult{err: stderrors.New("ORA-00060: deadlock detected while waiting for resource")}
+		}
+		return fakeResult{affected: 1}
+	})
+
+	attempts := 0
+	err := d.WithTx(context.Background(), nil, func(tx Tx) error {
+		attempts++
+		_, err := tx.ExecContext(context.Background(), "SELECT 1 FROM CONTRACT FOR UPDATE")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+	if attempts != maxTxAttempts {
+		t.Fatalf("%d attempts, want %d", attempts, maxTxAttempts)
+	}
+
+	statements := drv.recorded()
+	if statements[len(statements)-1] != "COMMIT" || strings.Count(fmt.Sprint(statements), "ROLLBACK") != maxTxAttempts-1 {
+		t.Fatalf("unexpected statements %q", statements)
+	}
+}
+
+func TestWithTxGivesUpAfterMaxAttempts(t *testing.T) {
+	d, _ := newFakeDB(t, DialectPostgres, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		return fakeResult{err: &pgError{code: "40001"}}
+	})
+
+	attempts := 0
+	err := d.WithTx(context.Background(), nil, func(tx Tx) error {
+		attempts++
+		_, err := tx.ExecContext(context.Background(), "UPDATE CONTRACT SET MEMO = $1", "x")
+		return err
+	})
+
+	var dbErr *errors.DatabaseError
+	if !stderrors.As(err, &dbErr) || dbErr.Operation != "UPDATE" || !IsRetryableTxError(err) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if attempts != maxTxAttempts {
+		t.Fatalf("%d attempts, want %d", attempts, maxTxAttempts)
+	}
+}
+
+func TestWithTxDoesNotRetryOtherErrors(t *testing.T) {
+	d, _ := newFakeDB(t, DialectOracle, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		return fakeResult{err: stderrors.New("ORA-00001: unique constraint violated")}
+	})
+
+	attempts := 0
+	err := d.WithTx(context.Background(), nil, func(tx Tx) error {
+		attempts++
+		_, err := tx.ExecContext(context.Background(), "UPDATE CONTRACT SET MEMO = :1", "x")
+		return err
+	})
+	if err == nil || attempts != 1 {
+		t.Fatalf("err %v after %d attempts", err, attempts)
+	}
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{stderrors.New("ORA-08177: can't serialize access for this transaction"), true},
+		{fmt.Errorf("query: %w", &pgError{code: "40P01"}), true},
+		{stderrors.New("ERROR: could not serialize access (SQLSTATE 40001)"), true},
+		{&pgError{code: "23505"}, false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRetryableTxError(tt.err); got != tt.want {
+			t.Errorf("IsRetryableTxError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
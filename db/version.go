@@ -0,0 +1,229 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+)
+
+// SchemaVersion is a ZR database schema version, e.g. 12.4.1
+type SchemaVersion struct {
+	Major int
+	Minor int
+	Patch int
+	Raw   string // Version as stored by ZR
+}
+
+// Range of ZR schema versions the repositories are written for: [minSchemaVersion, maxSchemaVersion)
+var (
+	minSchemaVersion = SchemaVersion{Major: 12}
+	maxSchemaVersion = SchemaVersion{Major: 14}
+
+	// changeFeedSchemaVersion added the CREATED / LASTMODIFIED columns read by change feeds
+	changeFeedSchemaVersion = SchemaVersion{Major: 12, Minor: 2}
+)
+
+// ParseSchemaVersion parses "12", "12.4" or "12.4.1", trailing build suffixes ("12.4.1-b37") are ignored
+func ParseSchemaVersion(value string) (SchemaVersion, error) {
+	raw := strings.TrimSpace(value)
+	core, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(raw, "v"), "V"), "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		parts = parts[:3]
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return SchemaVersion{}, fmt.Errorf("invalid schema version %q", value)
+		}
+		numbers[i] = n
+	}
+
+	return SchemaVersion{Major: numbers[0], Minor: numbers[1], Patch: numbers[2], Raw: raw}, nil
+}
+
+// IsZero reports whether the version is unknown
+func (v SchemaVersion) IsZero() bool {
+	return v.Major == 0 && v.Minor == 0 && v.Patch == 0
+}
+
+// Compare returns -1, 0 or 1 when v is lower than, equal to or higher than other
+func (v SchemaVersion) Compare(other SchemaVersion) int {
+	switch {
+	case v.Major != other.Major:
+		return cmpInt(v.Major, other.Major)
+	case v.Minor != other.Minor:
+		return cmpInt(v.Minor, other.Minor)
+	default:
+		return cmpInt(v.Patch, other.Patch)
+	}
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// String formats the version as major.minor.patch
+func (v SchemaVersion) String() string {
+	if v.IsZero() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Capabilities describes the detected ZR database and the features available on it
+type Capabilities struct {
+	Configured    bool          // Database access is configured
+	Detected      bool          // Schema detection ran, on the first connection
+	SchemaVersion SchemaVersion // Zero when the version could not be read
+	Supported     bool          // Version within the range the repositories support
+	ReadOnly      bool          // Degraded mode: write statements are refused
+	ChangeFeeds   bool          // Modification timestamps available for change feeds
+}
+
+// schemaState is the outcome of the schema detection, published once
+type schemaState struct {
+	caps Capabilities
+	err  error // Unsupported schema in strict mode, returned by every Conn
+}
+
+// Capabilities returns what was detected on the database, only the Configured flag
+// is set until the connection is opened
+func (d *DB) Capabilities() Capabilities {
+	if d == nil {
+		return Capabilities{}
+	}
+
+	var caps Capabilities
+	if state := d.schema.Load(); state != nil {
+		caps = state.caps
+	}
+	caps.Configured = true
+	return caps
+}
+
+// schemaCheck returns the configured schema check mode
+func (d *DB) schemaCheck() string {
+	if d.cfg.SchemaCheck == "" {
+		return config.SchemaCheckDegraded
+	}
+	return d.cfg.SchemaCheck
+}
+
+// detectSchema reads the ZR schema version, checks it against the supported range and
+// publishes the result. In strict mode an unsupported version fails every later Conn.
+func (d *DB) detectSchema(ctx context.Context, conn Querier) error {
+	mode := d.schemaCheck()
+	if mode == config.SchemaCheckOff {
+		d.publishSchema(schemaState{caps: Capabilities{Detected: true, Supported: true, ChangeFeeds: true}})
+		return nil
+	}
+
+	// Only a version or a missing version table answers the question for good: any other
+	// failure (canceled, connection lost, ...) is returned and detection runs again on the next Conn
+	version, err := d.readSchemaVersion(ctx, conn)
+	if err != nil && !isMissingTableError(err) {
+		d.logger.Warn("could not read ZR schema version, detecting again on the next use", logger.Error(err))
+		return errors.NewDatabaseError("failed to detect ZR schema version", "", "SELECT", err)
+	}
+	if err != nil {
+		d.logger.Warn("ZR schema version table not found", logger.Error(err))
+	}
+
+	supported := err == nil && version.Compare(minSchemaVersion) >= 0 && version.Compare(maxSchemaVersion) < 0
+
+	state := schemaState{caps: Capabilities{
+		Detected:      true,
+		SchemaVersion: version,
+		Supported:     supported,
+		ChangeFeeds:   supported && version.Compare(changeFeedSchemaVersion) >= 0,
+	}}
+
+	if supported {
+		d.logger.Info("ZR schema version detected", logger.String("version", version.String()))
+		d.publishSchema(state)
+		return nil
+	}
+
+	message := fmt.Sprintf("unsupported ZR schema version %s, supported: %s up to (excluding) %s", version, minSchemaVersion, maxSchemaVersion)
+
+	if mode == config.SchemaCheckStrict {
+		d.logger.Error(message)
+		state.err = errors.NewDatabaseError(message, "", "CONNECT", err)
+		d.publishSchema(state)
+		return state.err
+	}
+
+	state.caps.ReadOnly = true
+	d.logger.Warn(message + ", running in degraded read-only mode")
+	d.publishSchema(state)
+
+	return nil
+}
+
+// publishSchema makes the detection result visible, the read-only flag first so that
+// no write slips through once the capabilities report degraded mode
+func (d *DB) publishSchema(state schemaState) {
+	d.readOnly.Store(state.caps.ReadOnly)
+	d.schema.Store(&state)
+}
+
+// readSchemaVersion returns the highest version recorded in the ZR version table, zero when
+// it records none
+func (d *DB) readSchemaVersion(ctx context.Context, conn Querier) (SchemaVersion, error) {
+	query := selectFrom(versionTable, []string{versionColumn})
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return SchemaVersion{}, wrapError("failed to read schema version", query, err)
+	}
+	defer rows.Close()
+
+	var highest SchemaVersion
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return SchemaVersion{}, wrapError("failed to read schema version", query, err)
+		}
+
+		version, err := ParseSchemaVersion(raw)
+		if err != nil {
+			d.logger.Debug("skipping unparsable schema version", logger.String("version", raw))
+			continue
+		}
+		if version.Compare(highest) > 0 {
+			highest = version
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return SchemaVersion{}, wrapError("failed to read schema version", query, err)
+	}
+
+	if highest.IsZero() {
+		d.logger.Warn("no schema version recorded in " + versionTable)
+	}
+
+	return highest, nil
+}
+
+// errReadOnly is returned for write statements in degraded mode
+func (d *DB) errReadOnly(query string) error {
+	return errors.NewDatabaseError(
+		fmt.Sprintf("database is read-only, ZR schema version %s is not supported", d.Capabilities().SchemaVersion),
+		SanitizeQuery(query), operationOf(query), nil,
+	)
+}
@@ -0,0 +1,192 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+func TestParseSchemaVersion(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"12", "12.0.0"},
+		{"12.4", "12.4.0"},
+		{"V12.4.1-b37", "12.4.1"},
+		{"13.1.2.7", "13.1.2"},
+	}
+
+	for _, tt := range tests {
+		version, err := ParseSchemaVersion(tt.value)
+		if err != nil || version.String() != tt.want {
+			t.Errorf("ParseSchemaVersion(%q) = %s, %v; want %s", tt.value, version, err, tt.want)
+		}
+	}
+
+	if _, err := ParseSchemaVersion("twelve"); err == nil {
+		t.Error("expected an error for an invalid version")
+	}
+}
+
+func TestDegradedModeRefusesWrites(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{}, func(query string, args []driver.NamedValue) fakeResult {
+		return fakeResult{cols: []string{contractColID}, rows: [][]driver.Value{{int64(1)}}}
+	})
+	drv.schemaVersion = "11.2"
+
+	ctx := context.Background()
+	if _, _, err := NewContractRepository(d).FindByReference(ctx, models.ContractRefMemo, "crm-1"); err != nil {
+		t.Fatalf("reads must keep working: %v", err)
+	}
+	if caps := d.Capabilities(); !caps.Detected || caps.Supported || !caps.ReadOnly {
+		t.Fatalf("capabilities %+v", caps)
+	}
+
+	q, err := d.querier(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.ExecContext(ctx, "UPDATE CONTRACT SET MEMO = :1", "x"); err == nil {
+		t.Fatal("write executed in degraded mode")
+	}
+	if _, err := q.QueryContext(ctx, "INSERT INTO CONTRACT (NAME) VALUES (:1) RETURNING CONTRACTID", "x"); err == nil {
+		t.Fatal("row returning write executed in degraded mode")
+	}
+
+	err = d.WithTx(ctx, nil, func(tx Tx) error {
+		if _, err := tx.QueryContext(ctx, "SELECT CONTRACTID FROM CONTRACT"); err != nil {
+			return fmt.Errorf("read in transaction: %w", err)
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM CONTRACT WHERE CONTRACTID = :1", 1)
+		return err
+	})
+	if err == nil {
+		t.Fatal("write executed in a degraded mode transaction")
+	}
+
+	for _, statement := range drv.recorded() {
+		switch statement {
+		case "BEGIN":
+			t.Fatal("transaction not read-only in degraded mode")
+		case "UPDATE CONTRACT SET MEMO = :1", "DELETE FROM CONTRACT WHERE CONTRACTID = :1":
+			t.Fatalf("%q reached the database", statement)
+		}
+	}
+}
+
+func TestStrictModeFailsEveryConn(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{SchemaCheck: config.SchemaCheckStrict}, nil)
+	drv.schemaVersion = "14.0"
+
+	for range 2 {
+		if _, err := d.Conn(context.Background()); err == nil {
+			t.Fatal("expected the unsupported schema error")
+		}
+	}
+	if caps := d.Capabilities(); !caps.Detected || caps.Supported {
+		t.Fatalf("capabilities %+v", caps)
+	}
+}
+
+func TestDetectSchemaRetriesOnReadErrors(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{}, nil)
+	drv.versionErr = stderrors.New("connection reset by peer")
+
+	if _, err := d.Conn(context.Background()); err == nil {
+		t.Fatal("expected the read error")
+	}
+	if caps := d.Capabilities(); caps.Detected || caps.ReadOnly {
+		t.Fatalf("read error published as %+v", caps)
+	}
+
+	drv.versionErr = nil
+	if _, err := d.Conn(context.Background()); err != nil {
+		t.Fatalf("Conn: %v", err)
+	}
+	if caps := d.Capabilities(); !caps.Detected || !caps.Supported || caps.ReadOnly {
+		t.Fatalf("capabilities %+v", caps)
+	}
+}
+
+func TestDetectSchemaWithoutVersionTable(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{}, nil)
+	drv.schemaVersion = ""
+
+	if _, err := d.Conn(context.Background()); err != nil {
+		t.Fatalf("Conn: %v", err)
+	}
+	if caps := d.Capabilities(); !caps.Detected || caps.Supported || !caps.ReadOnly {
+		t.Fatalf("capabilities %+v", caps)
+	}
+}
+
+func TestSchemaCheckOff(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{SchemaCheck: config.SchemaCheckOff}, nil)
+	drv.schemaVersion = ""
+
+	if _, err := d.Conn(context.Background()); err != nil {
+		t.Fatalf("Conn: %v", err)
+	}
+	if caps := d.Capabilities(); !caps.Supported || caps.ReadOnly || !caps.ChangeFeeds {
+		t.Fatalf("capabilities %+v", caps)
+	}
+}
+
+func TestDetectSchemaWithoutHoldingTheLock(t *testing.T) {
+	d, drv := newFakeDB(t, DialectOracle, config.DBConfig{}, nil)
+	drv.versionStarted = make(chan struct{}, 4)
+	drv.versionRelease = make(chan struct{})
+
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = d.Conn(context.Background())
+		}()
+	}
+
+	select {
+	case <-drv.versionStarted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("schema version not queried")
+	}
+
+	// Capabilities and Raw stay available while the version is read
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if d.Capabilities().Detected || d.Raw() == nil {
+			t.Error("unexpected state during detection")
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("blocked during schema detection")
+	}
+
+	close(drv.versionRelease)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Conn %d: %v", i, err)
+		}
+	}
+	if n := len(drv.versionStarted); n != 0 {
+		t.Fatalf("schema version read %d extra times", n)
+	}
+	if !d.Capabilities().Supported {
+		t.Fatalf("capabilities %+v", d.Capabilities())
+	}
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+)
+
+// Watermark is the position of a change feed: the last delivered row, ordered by
+// modification time then ID, and the last delivered row without a modification time
+type Watermark struct {
+	Time         time.Time `json:"time"`
+	ID           int       `json:"id"`
+	UnmodifiedID int       `json:"unmodifiedId,omitempty"` // Rows with a NULL modification time are read by ID
+}
+
+// IsZero reports whether the feed has not delivered anything yet
+func (w Watermark) IsZero() bool {
+	return w.Time.IsZero() && w.ID == 0 && w.UnmodifiedID == 0
+}
+
+// after reports whether w is past other on the modification time keyset
+func (w Watermark) after(other Watermark) bool {
+	return w.Time.After(other.Time) || (w.Time.Equal(other.Time) && w.ID > other.ID)
+}
+
+// WatermarkStore persists the watermarks of change feeds, keyed by feed name
+type WatermarkStore interface {
+	// Load returns the watermark of a feed, the zero Watermark when none was saved
+	Load(ctx context.Context, feed string) (Watermark, error)
+
+	// Save stores the watermark of a feed
+	Save(ctx context.Context, feed string, mark Watermark) error
+}
+
+// MemoryWatermarkStore keeps watermarks in memory, they are lost on restart
+type MemoryWatermarkStore struct {
+	mu    sync.Mutex
+	marks map[string]Watermark
+}
+
+// NewMemoryWatermarkStore creates an in-memory watermark store
+func NewMemoryWatermarkStore() *MemoryWatermarkStore {
+	return &MemoryWatermarkStore{marks: make(map[string]Watermark)}
+}
+
+// Load implements WatermarkStore
+func (s *MemoryWatermarkStore) Load(ctx context.Context, feed string) (Watermark, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.marks[feed], nil
+}
+
+// Save implements WatermarkStore
+func (s *MemoryWatermarkStore) Save(ctx context.Context, feed string, mark Watermark) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marks[feed] = mark
+	return nil
+}
+
+// FileWatermarkStore keeps the watermarks of all feeds in a JSON file.
+// The file is replaced atomically, so a crash never leaves a partial file.
+type FileWatermarkStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileWatermarkStore creates a watermark store backed by the JSON file at path
+func NewFileWatermarkStore(path string) *FileWatermarkStore {
+	return &FileWatermarkStore{path: path}
+}
+
+// Load implements WatermarkStore
+func (s *FileWatermarkStore) Load(ctx context.Context, feed string) (Watermark, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	marks, err := s.read()
+	if err != nil {
+		return Watermark{}, err
+	}
+	return marks[feed], nil
+}
+
+// Save implements WatermarkStore
+func (s *FileWatermarkStore) Save(ctx context.Context, feed string, mark Watermark) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	marks, err := s.read()
+	if err != nil {
+		return err
+	}
+	marks[feed] = mark
+
+	data, err := json.MarshalIndent(marks, "", "  ")
+	if err != nil {
+		return errors.NewSDKError(errors.ErrorTypeInternal, "failed to encode watermarks", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return errors.NewSDKError(errors.ErrorTypeInternal, "failed to write watermark file", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.NewSDKError(errors.ErrorTypeInternal, "failed to write watermark file", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return errors.NewSDKError(errors.ErrorTypeInternal, "failed to write watermark file", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.NewSDKError(errors.ErrorTypeInternal, "failed to write watermark file", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return errors.NewSDKError(errors.ErrorTypeInternal, "failed to replace watermark file", err)
+	}
+	return nil
+}
+
+// read loads every watermark of the file, none when it does not exist yet
+func (s *FileWatermarkStore) read() (map[string]Watermark, error) {
+	marks := make(map[string]Watermark)
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return marks, nil
+	}
+	if err != nil {
+		return nil, errors.NewSDKError(errors.ErrorTypeInternal, "failed to read watermark file", err)
+	}
+
+	if err := json.Unmarshal(data, &marks); err != nil {
+		return nil, errors.NewSDKError(errors.ErrorTypeInternal, "invalid watermark file "+s.path, err)
+	}
+	return marks, nil
+}
@@ -0,0 +1,95 @@
+package bulk
+
+import (
+	"context"
+	"sync"
+
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// Run processes items with bounded concurrency and collects a per-item report.
+// No new item is started once ctx is done, or after the first failure when StopOnError is set;
+// items already running are left to finish.
+func Run[T any](ctx context.Context, items []T, opts models.BulkOptions, fn func(ctx context.Context, item T) (int, error)) *models.BulkReport {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	report := &models.BulkReport{Results: make([]models.BulkResult, len(items))}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		done    int
+		stopped bool
+	)
+
+	sem := make(chan struct{}, concurrency)
+
+	finish := func(res models.BulkResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		report.Results[res.Index] = res
+		if res.Err != nil && opts.StopOnError {
+			stopped = true
+		}
+
+		done++
+		if opts.Progress != nil {
+			opts.Progress(done, len(items))
+		}
+	}
+
+	isStopped := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return stopped
+	}
+
+	for i, item := range items {
+		if ctx.Err() != nil || isStopped() {
+			report.Results[i] = models.BulkResult{Index: i, Skipped: true}
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			report.Results[i] = models.BulkResult{Index: i, Skipped: true}
+			continue
+		}
+
+		// The slot may have been freed by a failing item
+		if isStopped() {
+			<-sem
+			report.Results[i] = models.BulkResult{Index: i, Skipped: true}
+			continue
+		}
+
+		wg.Add(1)
+		go func(index int, item T) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			id, err := fn(ctx, item)
+			finish(models.BulkResult{Index: index, ID: id, Err: err})
+		}(i, item)
+	}
+
+	wg.Wait()
+
+	for _, res := range report.Results {
+		switch {
+		case res.Skipped:
+			report.Skipped++
+		case res.Err != nil:
+			report.Failed++
+		default:
+			report.Succeeded++
+		}
+	}
+
+	return report
+}
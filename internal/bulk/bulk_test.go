@@ -0,0 +1,87 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+func TestRunReportsEveryItem(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6}
+	failure := errors.New("odd")
+
+	var running, peak atomic.Int32
+	progress := 0
+
+	report := Run(context.Background(), items, models.BulkOptions{
+		Concurrency: 2,
+		Progress:    func(done, total int) { progress = done },
+	}, func(ctx context.Context, item int) (int, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		if item%2 == 1 {
+			return item, failure
+		}
+		return item * 10, nil
+	})
+
+	if report.Succeeded != 3 || report.Failed != 3 || report.Skipped != 0 {
+		t.Fatalf("report %+v", report)
+	}
+	for i, res := range report.Results {
+		if res.Index != i {
+			t.Errorf("result %d has index %d", i, res.Index)
+		}
+		if items[i]%2 == 0 && res.ID != items[i]*10 {
+			t.Errorf("result %d has ID %d", i, res.ID)
+		}
+	}
+	if peak.Load() > 2 {
+		t.Errorf("%d items ran at once, concurrency is 2", peak.Load())
+	}
+	if progress != len(items) {
+		t.Errorf("progress reported %d, want %d", progress, len(items))
+	}
+}
+
+func TestRunStopOnError(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	report := Run(context.Background(), items, models.BulkOptions{Concurrency: 1, StopOnError: true},
+		func(ctx context.Context, item int) (int, error) {
+			if item == 2 {
+				return item, errors.New("fail")
+			}
+			return item, nil
+		})
+
+	if report.Succeeded != 1 || report.Failed != 1 || report.Skipped != 3 {
+		t.Fatalf("report %+v", report)
+	}
+}
+
+func TestRunSkipsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report := Run(ctx, []int{1, 2, 3}, models.BulkOptions{}, func(ctx context.Context, item int) (int, error) {
+		t.Fatal("item started after cancellation")
+		return 0, nil
+	})
+
+	if report.Skipped != 3 {
+		t.Fatalf("report %+v", report)
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/yassine-manai/go_zr_sdk/config"
 	"github.com/yassine-manai/go_zr_sdk/internal/errors"
@@ -18,21 +21,133 @@ import (
 // Client wraps http.Client with additional functionality
 type Client struct {
 	httpClient *http.Client
-	config     *config.Config
+	config     atomic.Pointer[config.Config] // Replaced by SetConfig
+	password   atomic.Pointer[string]        // Resolved UI password, never logged
+	refreshMu  sync.Mutex                    // Serializes password re-resolution
+	refreshes  atomic.Uint64                 // Completed password re-resolutions
+	limiter    rateLimiter
 	logger     logger.Logger
 }
 
 // NewClient creates a new HTTP client wrapper
 func NewClient(httpClient *http.Client, cfg *config.Config, log logger.Logger) *Client {
-	return &Client{
+	c := &Client{
 		httpClient: httpClient,
-		config:     cfg,
 		logger:     log,
 	}
+	c.SetConfig(cfg)
+	return c
+}
+
+// SetConfig replaces the configuration used by the next requests: host, credentials, timeout and rate limit.
+// A password given as a secret reference keeps the resolved password until SetPassword or ResolvePassword.
+func (c *Client) SetConfig(cfg *config.Config) {
+	c.config.Store(cfg)
+	c.limiter.set(cfg.UI.RateLimit, cfg.UI.RateBurst)
+
+	if !config.IsSecretRef(cfg.UI.Password) {
+		c.SetPassword(config.PlainSecret(cfg.UI.Password))
+	}
+}
+
+// SetPassword sets the resolved UI password sent with the next requests
+func (c *Client) SetPassword(password string) {
+	c.password.Store(&password)
+}
+
+// ResolvePassword resolves the configured UI password, plain or a secret reference, for the next requests
+func (c *Client) ResolvePassword(ctx context.Context) error {
+	password, err := config.ResolveSecret(ctx, c.config.Load().UI.Password)
+	if err != nil {
+		return err
+	}
+	c.SetPassword(password)
+	return nil
+}
+
+// currentPassword returns the resolved UI password
+func (c *Client) currentPassword() string {
+	if password := c.password.Load(); password != nil {
+		return *password
+	}
+	return ""
+}
+
+// refreshPassword re-resolves a password secret reference after the server rejected used,
+// refreshes being the number of re-resolutions completed when the request was sent, and reports
+// whether the request should be retried with a different password. Requests rejected at the
+// same time share a single re-resolution.
+func (c *Client) refreshPassword(ctx context.Context, used string, refreshes uint64) bool {
+	ref := c.config.Load().UI.Password
+	if !config.IsSecretRef(ref) {
+		return false
+	}
+
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	switch {
+	case c.currentPassword() != used:
+		// Already rotated by a concurrent request
+		return true
+	case c.refreshes.Load() != refreshes:
+		// Re-resolved by a concurrent request since this one was sent, without change
+		return false
+	}
+
+	password, err := config.ResolveSecret(ctx, ref)
+	c.refreshes.Add(1)
+	if err != nil {
+		c.logger.Error("failed to re-resolve UI password", logger.Error(err))
+		return false
+	}
+	if password == used {
+		return false
+	}
+
+	c.SetPassword(password)
+	c.logger.Info("UI password rotated")
+	return true
+}
+
+// timeout returns the configured request timeout, 0 = none
+func (c *Client) timeout() time.Duration {
+	cfg := c.config.Load()
+	if cfg.UI.Timeout > 0 {
+		return cfg.UI.Timeout
+	}
+	return cfg.Timeout
+}
+
+// requestContext bounds ctx by the configured request timeout
+func (c *Client) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := c.timeout()
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// streamContext bounds ctx by the configured request timeout until stop is called. A streamed
+// body is read after stop under ctx alone, so a slow consumer or a server returning its full
+// list is not cut off by a timeout meant for a single request.
+func (c *Client) streamContext(ctx context.Context) (streamCtx context.Context, stop func(), cancel context.CancelFunc) {
+	streamCtx, cancelCause := context.WithCancelCause(ctx)
+	stop = func() {}
+	if timeout := c.timeout(); timeout > 0 {
+		timer := time.AfterFunc(timeout, func() { cancelCause(context.DeadlineExceeded) })
+		stop = func() { timer.Stop() }
+	}
+	return streamCtx, stop, func() { cancelCause(context.Canceled) }
 }
 
 // DoRequest executes an HTTP request and handles response
 func (c *Client) DoRequest(ctx context.Context, req *http.Request) (*http.Response, error) {
+	// Client-side rate limit
+	if err := c.limiter.wait(ctx); err != nil {
+		return nil, errors.NewNetworkError("HTTP request cancelled while rate limited", err)
+	}
+
 	// Add default headers
 	c.addDefaultHeaders(req)
 
@@ -54,22 +169,77 @@ func (c *Client) DoRequest(ctx context.Context, req *http.Request) (*http.Respon
 
 // DoXML executes an XML request
 func (c *Client) DoXMLRequest(ctx context.Context, method, path string, body any, result any) error {
-	req, err := c.buildXMLRequest(ctx, method, path, body)
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+
+	resp, err := c.send(ctx, method, path, body)
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
+
+	return c.handleXMLResponse(resp, result)
+}
+
+// DoXMLStream executes an XML request and hands the response body to fn as a streaming decoder,
+// so large lists can be processed without loading them in memory. The request timeout applies
+// until the response headers arrive; fn then runs for as long as ctx allows.
+func (c *Client) DoXMLStream(ctx context.Context, method, path string, body any, fn func(dec *xml.Decoder) error) error {
+	ctx, stop, cancel := c.streamContext(ctx)
+	defer cancel()
 
-	resp, err := c.DoRequest(ctx, req)
+	resp, err := c.send(ctx, method, path, body)
+	stop()
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	return c.handleXMLResponse(resp, result)
+	if resp.StatusCode >= 400 {
+		errBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return errors.NewNetworkError("failed to read response body", err)
+		}
+		return c.handleErrorResponse(resp.StatusCode, errBody)
+	}
+
+	if err := fn(xml.NewDecoder(resp.Body)); err != nil {
+		if ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// send builds and executes an XML request. When the server rejects the credentials and the password
+// is a secret reference, the reference is resolved again and the request retried once if it changed.
+func (c *Client) send(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := c.buildXMLRequest(ctx, method, path, body)
+		if err != nil {
+			return nil, err
+		}
+
+		used, refreshes := c.currentPassword(), c.refreshes.Load()
+		resp, err := c.DoRequest(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 || !c.refreshPassword(ctx, used, refreshes) {
+			return resp, nil
+		}
+
+		resp.Body.Close()
+		c.logger.Info("retrying HTTP request with rotated credentials", logger.String("method", method), logger.String("path", path))
+	}
 }
 
 func (c *Client) buildXMLRequest(ctx context.Context, method, path string, body any) (*http.Request, error) {
-	url := c.config.UI.Host + c.config.UI.BasePath + path
+	cfg := c.config.Load()
+	url := cfg.UI.Host + cfg.UI.BasePath + path
 
 	var bodyReader io.Reader
 	if body != nil {
@@ -137,7 +307,7 @@ func (c *Client) handleXMLResponse(resp *http.Response, result interface{}) erro
 // addDefaultHeaders adds common headers to request
 func (c *Client) addDefaultHeaders(req *http.Request) {
 	// Basic Authentication (encode username:password)
-	auth := c.config.UI.Username + ":" + c.config.UI.Password
+	auth := c.config.Load().UI.Username + ":" + c.currentPassword()
 	encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
 	req.Header.Set("Authorization", "Basic "+encodedAuth)
 
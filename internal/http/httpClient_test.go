@@ -0,0 +1,195 @@
+package http
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+)
+
+// secretCmd writes a cmd: reference printing the content of the returned password file,
+// and counts its runs in the returned counter file
+func secretCmd(t *testing.T, password string) (ref, passwordFile, counter string) {
+	t.Helper()
+	dir := t.TempDir()
+	passwordFile = filepath.Join(dir, "password")
+	counter = filepath.Join(dir, "runs")
+	script := filepath.Join(dir, "secret.sh")
+	writeFile(t, passwordFile, password)
+	writeFile(t, script, "#!/bin/sh\necho run >> "+counter+"\ncat "+passwordFile+"\n")
+	if err := os.Chmod(script, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	return config.SecretCmdPrefix + script, passwordFile, counter
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func runs(t *testing.T, counter string) int {
+	t.Helper()
+	data, err := os.ReadFile(counter)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "run")
+}
+
+// authServer accepts the password "accepted" and holds the first n requests until they all arrived
+func authServer(t *testing.T, n int) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	arrived := 0
+	all := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrived++
+		if arrived == n {
+			close(all)
+		}
+		mu.Unlock()
+		<-all
+
+		if _, password, _ := r.BasicAuth(); password != "accepted" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(srv *httptest.Server, password string) *Client {
+	cfg := &config.Config{UI: config.UIConfig{Host: srv.URL, Username: "user", Password: password, Timeout: 5 * time.Second}}
+	return NewClient(srv.Client(), cfg, logger.NewNoOpLogger())
+}
+
+func requestTogether(c *Client, n int) []error {
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = c.DoXMLRequest(context.Background(), http.MethodGet, "/", nil, nil)
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+func TestClientSharesPasswordRotation(t *testing.T) {
+	ref, passwordFile, counter := secretCmd(t, "rejected")
+	c := newTestClient(authServer(t, 4), ref)
+	if err := c.ResolvePassword(context.Background()); err != nil {
+		t.Fatalf("ResolvePassword: %v", err)
+	}
+	writeFile(t, passwordFile, "accepted")
+
+	for i, err := range requestTogether(c, 4) {
+		if err != nil {
+			t.Errorf("request %d: %v", i, err)
+		}
+	}
+	if got := runs(t, counter); got != 2 {
+		t.Errorf("secret command ran %d times, want 2", got)
+	}
+}
+
+func TestClientSharesUnchangedResolution(t *testing.T) {
+	ref, _, counter := secretCmd(t, "rejected")
+	c := newTestClient(authServer(t, 4), ref)
+	if err := c.ResolvePassword(context.Background()); err != nil {
+		t.Fatalf("ResolvePassword: %v", err)
+	}
+
+	for i, err := range requestTogether(c, 4) {
+		if err == nil {
+			t.Errorf("request %d: expected an authentication error", i)
+		}
+	}
+	if got := runs(t, counter); got != 2 {
+		t.Errorf("secret command ran %d times, want 2", got)
+	}
+}
+
+func TestSetConfigPlainPasswordEscape(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, password, _ := r.BasicAuth(); password != "env:accepted" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newTestClient(srv, "plain:env:accepted")
+	if err := c.DoXMLRequest(context.Background(), http.MethodGet, "/", nil, nil); err != nil {
+		t.Fatalf("DoXMLRequest: %v", err)
+	}
+}
+
+func TestDoXMLStreamOutlivesRequestTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "<items>")
+		w.(http.Flusher).Flush()
+		for i := range 20000 {
+			fmt.Fprintf(w, "<item>%d</item>", i)
+		}
+		io.WriteString(w, "</items>")
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := &config.Config{UI: config.UIConfig{Host: srv.URL, Username: "user", Password: "pass", Timeout: 100 * time.Millisecond}}
+	c := NewClient(srv.Client(), cfg, logger.NewNoOpLogger())
+
+	var count int
+	err := c.DoXMLStream(context.Background(), http.MethodGet, "/", nil, func(dec *xml.Decoder) error {
+		n, err := DecodeEach(dec, "item", func(item int) bool {
+			if item == 0 {
+				time.Sleep(200 * time.Millisecond) // Slow consumer, past the request timeout
+			}
+			return true
+		})
+		count = n
+		return err
+	})
+	if err != nil || count != 20000 {
+		t.Fatalf("decoded %d items: %v", count, err)
+	}
+}
+
+func TestDoXMLStreamTimesOutWaitingForHeaders(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	cfg := &config.Config{UI: config.UIConfig{Host: srv.URL, Username: "user", Password: "pass", Timeout: 20 * time.Millisecond}}
+	c := NewClient(srv.Client(), cfg, logger.NewNoOpLogger())
+
+	err := c.DoXMLStream(context.Background(), http.MethodGet, "/", nil, func(dec *xml.Decoder) error { return nil })
+	if !errors.IsNetworkError(err) {
+		t.Fatalf("got %v, want a network error", err)
+	}
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket limiting the requests sent to the ZR web service.
+// A zero rate lets every request through.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // Tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// set changes the rate and burst, keeping the tokens already available
+func (r *rateLimiter) set(rate float64, burst int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if burst <= 0 {
+		burst = 1
+	}
+
+	if r.rate <= 0 {
+		// Enabling the limit starts with a full bucket
+		r.tokens = float64(burst)
+		r.last = time.Now()
+	}
+
+	r.rate = rate
+	r.burst = float64(burst)
+	r.tokens = min(r.tokens, r.burst)
+}
+
+// wait blocks until a request may be sent or ctx is done
+func (r *rateLimiter) wait(ctx context.Context) error {
+	for {
+		delay := r.reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token, or returns how long to wait for the next one
+func (r *rateLimiter) reserve() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.rate <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	r.tokens = min(r.burst, r.tokens+now.Sub(r.last).Seconds()*r.rate)
+	r.last = now
+
+	if r.tokens >= 1 {
+		r.tokens--
+		return 0
+	}
+	return time.Duration((1 - r.tokens) / r.rate * float64(time.Second))
+}
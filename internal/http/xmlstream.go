@@ -0,0 +1,39 @@
+package http
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+)
+
+// DecodeEach decodes every element named local from a streaming decoder and passes it to yield.
+// It stops as soon as yield returns false and returns the number of decoded elements.
+func DecodeEach[T any](dec *xml.Decoder, local string, yield func(T) bool) (int, error) {
+	count := 0
+
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, errors.NewSDKError(errors.ErrorTypeInternal, "failed to parse XML response", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != local {
+			continue
+		}
+
+		var item T
+		if err := dec.DecodeElement(&item, &start); err != nil {
+			return count, errors.NewSDKError(errors.ErrorTypeInternal, "failed to parse XML response", err)
+		}
+		count++
+
+		if !yield(item) {
+			return count, nil
+		}
+	}
+}
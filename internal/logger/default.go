@@ -7,12 +7,13 @@ import (
 	"io"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // DefaultLogger is a simple logger implementation
 type DefaultLogger struct {
-	level       Level
+	level       *atomic.Int32 // Shared with child loggers, changed by SetLevel
 	output      io.Writer
 	mu          sync.Mutex
 	fields      []Field
@@ -32,44 +33,57 @@ func NewDefaultLogger(opts DefaultLoggerOptions) *DefaultLogger {
 		opts.Output = os.Stdout
 	}
 
+	level := &atomic.Int32{}
+	level.Store(int32(opts.Level))
+
 	return &DefaultLogger{
-		level:       opts.Level,
+		level:       level,
 		output:      opts.Output,
 		fields:      make([]Field, 0),
 		prettyPrint: opts.PrettyPrint,
 	}
 }
 
+// Level returns the minimum level logged
+func (l *DefaultLogger) Level() Level {
+	return Level(l.level.Load())
+}
+
+// SetLevel changes the minimum level logged, for this logger and the loggers derived with With
+func (l *DefaultLogger) SetLevel(level Level) {
+	l.level.Store(int32(level))
+}
+
 // Debug logs a debug message
 func (l *DefaultLogger) Debug(msg string, fields ...Field) {
-	if l.level <= LevelDebug {
+	if l.Level() <= LevelDebug {
 		l.Log(LevelDebug, msg, fields...)
 	}
 }
 
 // Info logs an info message
 func (l *DefaultLogger) Info(msg string, fields ...Field) {
-	if l.level <= LevelInfo {
+	if l.Level() <= LevelInfo {
 		l.Log(LevelInfo, msg, fields...)
 	}
 }
 
 // Warn logs a warning message
 func (l *DefaultLogger) Warn(msg string, fields ...Field) {
-	if l.level <= LevelWarn {
+	if l.Level() <= LevelWarn {
 		l.Log(LevelWarn, msg, fields...)
 	}
 }
 
 // Error logs an error message
 func (l *DefaultLogger) Error(msg string, fields ...Field) {
-	if l.level <= LevelError {
+	if l.Level() <= LevelError {
 		l.Log(LevelError, msg, fields...)
 	}
 }
 
 func (l *DefaultLogger) Trace(msg string, fields ...Field) {
-	if l.level <= LevelTrace {
+	if l.Level() <= LevelTrace {
 		l.Log(LevelTrace, msg, fields...)
 	}
 }
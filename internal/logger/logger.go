@@ -57,6 +57,29 @@ type Logger interface {
 	WithContext(ctx context.Context) Logger
 }
 
+// LevelSetter is implemented by loggers whose level can be changed at runtime
+type LevelSetter interface {
+	SetLevel(level Level)
+}
+
+// LevelGetter is implemented by loggers reporting the minimum level they log
+type LevelGetter interface {
+	Level() Level
+}
+
+// Enabled reports whether l logs messages of level, so callers can skip building costly fields.
+// Loggers not reporting their level are assumed to log everything.
+func Enabled(l Logger, level Level) bool {
+	switch v := l.(type) {
+	case nil, *NoOpLogger:
+		return false
+	case LevelGetter:
+		return v.Level() <= level
+	default:
+		return true
+	}
+}
+
 // Field represents a key-value pair for structured logging
 type Field struct {
 	Key   string
@@ -0,0 +1,75 @@
+package paging
+
+import (
+	"context"
+	"iter"
+)
+
+// DefaultPageSize is used when no page size is configured
+const DefaultPageSize = 100
+
+// FetchFunc fetches the page starting at offset and passes every item to yield.
+// It must stop reading as soon as yield returns false, and returns the number of items read.
+type FetchFunc[T any] func(ctx context.Context, offset, limit int, yield func(T) bool) (int, error)
+
+// Iterate returns a sequence walking every page returned by fetch.
+//
+// Servers that do not paginate return the full list on the first request: a page longer than
+// the page size, or a page starting with the same item as the first page, ends the iteration.
+func Iterate[T any, K comparable](ctx context.Context, pageSize int, key func(T) K, fetch FetchFunc[T]) iter.Seq2[T, error] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return func(yield func(T, error) bool) {
+		var (
+			zero     T
+			firstKey K
+			offset   int
+		)
+
+		for page := 0; ; page++ {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			stopped, repeated := false, false
+			index := 0
+
+			n, err := fetch(ctx, offset, pageSize, func(item T) bool {
+				if index == 0 {
+					if page == 0 {
+						firstKey = key(item)
+					} else if key(item) == firstKey {
+						repeated = true
+						return false
+					}
+				}
+				index++
+
+				if !yield(item, nil) {
+					stopped = true
+					return false
+				}
+				return true
+			})
+
+			if stopped || repeated {
+				return
+			}
+
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			// Short page: last one. Oversized page: the server ignored paging and streamed everything.
+			if n != pageSize {
+				return
+			}
+
+			offset += n
+		}
+	}
+}
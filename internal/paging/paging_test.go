@@ -0,0 +1,110 @@
+package paging
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fetchFrom serves items as pages, or all at once when the server ignores paging
+func fetchFrom(items []int, paged bool, calls *int) FetchFunc[int] {
+	return func(ctx context.Context, offset, limit int, yield func(int) bool) (int, error) {
+		*calls++
+
+		start, end := 0, len(items)
+		if paged {
+			start, end = min(offset, len(items)), min(offset+limit, len(items))
+		}
+
+		n := 0
+		for _, item := range items[start:end] {
+			n++
+			if !yield(item) {
+				break
+			}
+		}
+		return n, nil
+	}
+}
+
+func identity(i int) int { return i }
+
+func TestIterate(t *testing.T) {
+	tests := []struct {
+		name      string
+		count     int
+		paged     bool
+		wantCalls int
+	}{
+		{"empty", 0, true, 1},
+		{"short page", 5, true, 1},
+		{"exact pages", 20, true, 3},
+		{"several pages", 25, true, 3},
+		{"unpaged server", 25, false, 1},
+		{"unpaged server, page sized", 10, false, 2},
+	}
+
+	for _, tt := range tests {
+		items := make([]int, tt.count)
+		for i := range items {
+			items[i] = i + 1
+		}
+
+		calls := 0
+		var got []int
+		for item, err := range Iterate(context.Background(), 10, identity, fetchFrom(items, tt.paged, &calls)) {
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			got = append(got, item)
+		}
+
+		if len(got) != tt.count {
+			t.Errorf("%s: got %d items, want %d", tt.name, len(got), tt.count)
+		}
+		for i, item := range got {
+			if item != i+1 {
+				t.Errorf("%s: item %d is %d", tt.name, i, item)
+				break
+			}
+		}
+		if calls != tt.wantCalls {
+			t.Errorf("%s: %d fetches, want %d", tt.name, calls, tt.wantCalls)
+		}
+	}
+}
+
+func TestIterateStopsOnBreak(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i + 1
+	}
+
+	calls := 0
+	seen := 0
+	for range Iterate(context.Background(), 10, identity, fetchFrom(items, true, &calls)) {
+		seen++
+		if seen == 15 {
+			break
+		}
+	}
+
+	if calls != 2 {
+		t.Fatalf("%d fetches after breaking on the second page, want 2", calls)
+	}
+}
+
+func TestIterateReportsErrors(t *testing.T) {
+	failure := errors.New("boom")
+	fetch := func(ctx context.Context, offset, limit int, yield func(int) bool) (int, error) {
+		return 0, failure
+	}
+
+	for _, err := range Iterate(context.Background(), 10, identity, fetch) {
+		if !errors.Is(err, failure) {
+			t.Fatalf("got %v, want %v", err, failure)
+		}
+		return
+	}
+	t.Fatal("no error reported")
+}
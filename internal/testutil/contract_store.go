@@ -0,0 +1,110 @@
+package testutil
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// ContractStore is an in-memory ZR contract web service
+type ContractStore struct {
+	mu       sync.Mutex
+	Items    map[int]models.ContractDetail
+	nextID   int
+	Posts    int
+	Details  int // Detail GETs
+	FailPost int // POSTs answered 503 after being committed
+}
+
+// NewContractStore returns an empty store numbering contracts from 100
+func NewContractStore() *ContractStore {
+	return &ContractStore{Items: map[int]models.ContractDetail{}, nextID: 100}
+}
+
+// Add stores a contract and returns its ID
+func (s *ContractStore) Add(d models.ContractDetail) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	d.Contract.ID = &id
+	s.Items[id] = d
+	return id
+}
+
+// Get returns a stored contract
+func (s *ContractStore) Get(id int) models.ContractDetail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Items[id]
+}
+
+// ServeHTTP serves the contract list, details, creations, updates and deletions
+func (s *ContractStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var id int
+	fmt.Sscanf(strings.TrimPrefix(r.URL.Path, models.ContractCustomerMedia+"/"), "%d", &id)
+
+	switch {
+	case r.Method == http.MethodGet && id == 0:
+		ids := make([]int, 0, len(s.Items))
+		for id := range s.Items {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+
+		io.WriteString(w, `<contracts xmlns="http://gsph.sub.com/cust/types">`)
+		for _, id := range ids {
+			c := s.Items[id].Contract
+			fmt.Fprintf(w, `<contract><id>%d</id><name>%s</name><xValidFrom>%s</xValidFrom><xValidUntil>%s</xValidUntil></contract>`,
+				id, c.Name, c.ValidFrom, c.ValidUntil)
+		}
+		io.WriteString(w, `</contracts>`)
+
+	case r.Method == http.MethodGet:
+		s.Details++
+		d, ok := s.Items[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		out, _ := xml.Marshal(d)
+		w.Write(out)
+
+	case r.Method == http.MethodPost, r.Method == http.MethodPut:
+		var d models.ContractDetail
+		body, _ := io.ReadAll(r.Body)
+		if err := xml.Unmarshal(body, &d); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			id = s.nextID
+			s.nextID++
+			s.Posts++
+		}
+		d.Contract.ID = &id
+		s.Items[id] = d
+
+		if r.Method == http.MethodPost && s.FailPost > 0 {
+			s.FailPost--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		out, _ := xml.Marshal(d)
+		w.Write(out)
+
+	case r.Method == http.MethodDelete:
+		delete(s.Items, id)
+	}
+}
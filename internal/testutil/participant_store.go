@@ -0,0 +1,130 @@
+package testutil
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// ParticipantStore is an in-memory ZR participant web service
+type ParticipantStore struct {
+	mu      sync.Mutex
+	Items   map[int]models.ParticipantDetail
+	nextID  int
+	Gets    int                        // Participant detail GETs
+	Puts    []models.ParticipantDetail // Bodies of the detail PUTs, in order
+	FailPut func(n int) bool           // Answers the n-th PUT (from 1) with 503 when it returns true
+}
+
+// NewParticipantStore returns an empty store numbering participants from 100
+func NewParticipantStore() *ParticipantStore {
+	return &ParticipantStore{Items: map[int]models.ParticipantDetail{}, nextID: 100}
+}
+
+// Add stores a participant and returns its ID
+func (s *ParticipantStore) Add(d models.ParticipantDetail) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	d.Participant.ID = &id
+	s.Items[id] = d
+	return id
+}
+
+// Get returns a stored participant
+func (s *ParticipantStore) Get(id int) models.ParticipantDetail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Items[id]
+}
+
+// ids returns the participant IDs in order, restricted to a contract unless contractID is 0
+func (s *ParticipantStore) ids(contractID int) []int {
+	var ids []int
+	for id, d := range s.Items {
+		if contractID == 0 || d.Participant.ContractID == contractID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// ServeHTTP serves the contracts of the stored participants and the participant list, details,
+// creations, updates and deletions
+func (s *ParticipantStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, models.ContractCustomerMedia)
+	if path == "" {
+		seen := map[int]bool{}
+		io.WriteString(w, `<contracts xmlns="http://gsph.sub.com/cust/types">`)
+		for _, id := range s.ids(0) {
+			if c := s.Items[id].Participant.ContractID; !seen[c] {
+				seen[c] = true
+				fmt.Fprintf(w, `<contract><id>%d</id><name>C%d</name></contract>`, c, c)
+			}
+		}
+		io.WriteString(w, `</contracts>`)
+		return
+	}
+
+	var contractID, id int
+	fmt.Sscanf(path, "/%d/consumers/%d", &contractID, &id)
+
+	switch {
+	case r.Method == http.MethodGet && id == 0:
+		io.WriteString(w, `<consumers xmlns="http://gsph.sub.com/cust/types">`)
+		for _, id := range s.ids(contractID) {
+			p := s.Items[id].Participant
+			fmt.Fprintf(w, `<consumer><id>%d</id><contractid>%d</contractid><name>%s</name></consumer>`, id, contractID, p.Name)
+		}
+		io.WriteString(w, `</consumers>`)
+
+	case r.Method == http.MethodGet:
+		s.Gets++
+		d, ok := s.Items[id]
+		if !ok || d.Participant.ContractID != contractID {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		out, _ := xml.Marshal(d)
+		w.Write(out)
+
+	case r.Method == http.MethodPost, r.Method == http.MethodPut:
+		var d models.ParticipantDetail
+		body, _ := io.ReadAll(r.Body)
+		if err := xml.Unmarshal(body, &d); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			id = s.nextID
+			s.nextID++
+		} else {
+			s.Puts = append(s.Puts, d)
+			if s.FailPut != nil && s.FailPut(len(s.Puts)) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		d.Participant.ID = &id
+		d.Participant.ContractID = contractID
+		s.Items[id] = d
+		out, _ := xml.Marshal(d)
+		w.Write(out)
+
+	case r.Method == http.MethodDelete:
+		delete(s.Items, id)
+	}
+}
@@ -0,0 +1,29 @@
+// Package testutil holds the fakes shared by the service tests: a test server wired to the
+// internal HTTP client, and in-memory contract and participant web services.
+package testutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/config"
+	internalhttp "github.com/yassine-manai/go_zr_sdk/internal/http"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+)
+
+// NewHTTPClient returns an HTTP client talking to a test server running handler,
+// closed when the test ends
+func NewHTTPClient(t testing.TB, handler http.HandlerFunc) *internalhttp.Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	cfg := &config.Config{
+		UI:      config.UIConfig{Host: srv.URL, Username: "user", Password: "pass"},
+		Timeout: 5 * time.Second,
+	}
+	return internalhttp.NewClient(srv.Client(), cfg, logger.NewNoOpLogger())
+}
@@ -0,0 +1,40 @@
+package models
+
+// BulkOptions configures a bulk operation
+type BulkOptions struct {
+	Concurrency int                   // Parallel requests, 0 = 1
+	StopOnError bool                  // Stop starting new items after the first failure
+	Progress    func(done, total int) // Called after every finished item (optional)
+}
+
+// BulkResult is the outcome of a single item of a bulk operation
+type BulkResult struct {
+	Index   int   // Position of the item in the input
+	ID      int   // Resulting resource ID on success
+	Err     error // Typed SDK error on failure
+	Skipped bool  // Item was never started (stop-on-error or cancellation)
+}
+
+// Success reports whether the item was processed without error
+func (r BulkResult) Success() bool {
+	return !r.Skipped && r.Err == nil
+}
+
+// BulkReport holds the per-item results of a bulk operation, in input order
+type BulkReport struct {
+	Results   []BulkResult
+	Succeeded int
+	Failed    int
+	Skipped   int
+}
+
+// Errors returns the failed results
+func (r *BulkReport) Errors() []BulkResult {
+	failed := make([]BulkResult, 0, r.Failed)
+	for _, res := range r.Results {
+		if !res.Skipped && res.Err != nil {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
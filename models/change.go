@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// ChangeType is the kind of change reported by a change feed
+type ChangeType string
+
+const (
+	ChangeCreated ChangeType = "created"
+	ChangeUpdated ChangeType = "updated"
+	ChangeDeleted ChangeType = "deleted" // Soft deleted (archived) in ZR
+)
+
+// ChangeEvent reports a changed contract or participant.
+// Feeds deliver at least once: consumers must tolerate the same event twice.
+type ChangeEvent struct {
+	Type       ChangeType
+	Entity     string    // "contract" or "participant"
+	ID         int       // Contract or participant ID
+	ContractID int       // Owning contract, equal to ID for contracts
+	ModifiedAt time.Time // Modification timestamp of the row, zero when the row has none
+
+	Contract    *ContractDetail    // Set for contract events
+	Participant *ParticipantDetail // Set for participant events
+}
@@ -2,9 +2,21 @@ package models
 
 import (
 	"encoding/xml"
+	"fmt"
 	"time"
 )
 
+// DateLayout is the date format used by the ZR API ("2021-01-01")
+const DateLayout = "2006-01-02"
+
+// dateLayouts lists the date representations returned by the ZR API
+var dateLayouts = []string{
+	DateLayout,
+	"2006-01-02Z07:00",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+}
+
 // Timestamp represents a standardized timestamp
 type Timestamp struct {
 	time.Time
@@ -20,6 +32,88 @@ func Now() Timestamp {
 	return Timestamp{Time: time.Now()}
 }
 
+// ParseDate parses a ZR date value
+func ParseDate(value string) (time.Time, error) {
+	return ParseDateIn(value, time.UTC)
+}
+
+// ParseDateIn parses a ZR date value, reading values without a zone in the given location
+func ParseDateIn(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid ZR date: %q", value)
+}
+
+// FormatDate formats a time as a ZR date
+func FormatDate(t time.Time) string {
+	return t.Format(DateLayout)
+}
+
+// SameDate compares two ZR dates, falling back to string equality when unparsable
+func SameDate(a, b string) bool {
+	ta, errA := ParseDate(a)
+	tb, errB := ParseDate(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return ta.Equal(tb) || FormatDate(ta) == FormatDate(tb)
+}
+
+// IsValidOn reports whether the validity window [from, until] covers the given day.
+// An empty or unparsable bound is treated as open.
+func IsValidOn(from, until string, day time.Time) bool {
+	day = truncateDay(day)
+
+	if start, err := ParseDate(from); err == nil && day.Before(truncateDay(start)) {
+		return false
+	}
+
+	if end, err := ParseDate(until); err == nil && day.After(truncateDay(end)) {
+		return false
+	}
+
+	return true
+}
+
+// truncateDay drops the time of day, keeping the calendar date
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Period is a calendar period, such as a contract term
+type Period struct {
+	Years  int
+	Months int
+	Days   int
+}
+
+// IsZero reports whether the period is empty
+func (p Period) IsZero() bool {
+	return p.Years == 0 && p.Months == 0 && p.Days == 0
+}
+
+// AddTo returns t shifted by the period
+func (p Period) AddTo(t time.Time) time.Time {
+	return t.AddDate(p.Years, p.Months, p.Days)
+}
+
+// PageOptions configures iterator-based list operations
+type PageOptions struct {
+	PageSize int // Items requested per page, 0 = default (100)
+}
+
+// UpsertAction reports what an upsert operation did
+type UpsertAction string
+
+const (
+	UpsertCreated   UpsertAction = "created"
+	UpsertUpdated   UpsertAction = "updated"
+	UpsertUnchanged UpsertAction = "unchanged"
+)
+
 // Metadata contains common metadata for responses
 type Metadata struct {
 	RequestID string    `json:"request_id,omitempty"`
@@ -12,14 +12,62 @@ type ContractDetail struct {
 	StdAddr            *StdAddr            `xml:"stdAddr,omitempty"`
 	Counting           int                 `xml:"counting,omitempty"`
 	Present            int                 `xml:"present,omitempty"`
-	Status             int                 `xml:"status,omitempty"`
-	Delete             int                 `xml:"delete,omitempty"`
+	Status             *int                `xml:"status,omitempty"` // Sent when set, so a detail built from a request leaves the status alone
+	Delete             *int                `xml:"delete,omitempty"` // Sent when set, like Status
 	Memo               string              `xml:"memo,omitempty"`
 	InvoiceGroup       int                 `xml:"invoicegroup,omitempty"`
 	TaxIDNo            string              `xml:"taxIdNo,omitempty"`
 	IDNo               string              `xml:"idNo,omitempty"`
 }
 
+// Contract status values (ContractDetail.Status)
+const (
+	ContractStatusActive  = 0
+	ContractStatusBlocked = 1
+)
+
+// StatusValue returns the contract status, active when none was read
+func (d *ContractDetail) StatusValue() int {
+	if d.Status == nil {
+		return ContractStatusActive
+	}
+	return *d.Status
+}
+
+// SetStatus sets the status sent with the detail, including ContractStatusActive
+func (d *ContractDetail) SetStatus(status int) {
+	d.Status = &status
+}
+
+// SetDelete sets the deletion flag sent with the detail, including 0
+func (d *ContractDetail) SetDelete(flag int) {
+	d.Delete = &flag
+}
+
+// IsBlocked reports whether the contract is blocked
+func (d *ContractDetail) IsBlocked() bool {
+	return d.StatusValue() == ContractStatusBlocked
+}
+
+// IsDeleted reports whether the contract carries the deletion flag
+func (d *ContractDetail) IsDeleted() bool {
+	return d.Delete != nil && *d.Delete != 0
+}
+
+// ToList returns the contract list entry of the detail
+func (d *ContractDetail) ToList() ContractList {
+	list := ContractList{
+		Name:       d.Contract.Name,
+		ValidFrom:  d.Contract.ValidFrom,
+		ValidUntil: d.Contract.ValidUntil,
+		FilialID:   d.Contract.FilialID,
+	}
+	if d.Contract.ID != nil {
+		list.ID = *d.Contract.ID
+	}
+	return list
+}
+
 // Contract represents the basic contract info
 type Contract struct {
 	Href       string   `xml:"href,attr,omitempty"`
@@ -79,6 +127,12 @@ type ContractRequest struct {
 	ValidFrom  string // Required - Format: "2021-01-01"
 	ValidUntil string // Required - Format: "2021-12-31"
 	StdAddr    *StdAddr
+	Memo       string
+
+	// IdempotencyKey is an external key making creation safe to retry (optional).
+	// It is stored in the contract's reference field (memo by default), so Memo must stay
+	// empty unless another reference field is selected.
+	IdempotencyKey string
 }
 
 // Contracts represents the root XML element containing multiple contracts
@@ -107,5 +161,45 @@ func (r ContractRequest) ToXML() ContractDetail {
 			ValidUntil: r.ValidUntil,
 			StdAddr:    r.StdAddr,
 		},
+		Memo: r.Memo,
+	}
+}
+
+// ContractRefField selects the contract field holding an external reference
+type ContractRefField string
+
+const (
+	ContractRefMemo      ContractRefField = "memo"
+	ContractRefMatchCode ContractRefField = "matchCode"
+	ContractRefIDNo      ContractRefField = "idNo"
+)
+
+// Get returns the reference stored in the contract
+func (f ContractRefField) Get(d *ContractDetail) string {
+	switch f {
+	case ContractRefMatchCode:
+		if d.Person == nil {
+			return ""
+		}
+		return d.Person.MatchCode
+	case ContractRefIDNo:
+		return d.IDNo
+	default:
+		return d.Memo
+	}
+}
+
+// Set stores the reference in the contract
+func (f ContractRefField) Set(d *ContractDetail, value string) {
+	switch f {
+	case ContractRefMatchCode:
+		if d.Person == nil {
+			d.Person = &Person{}
+		}
+		d.Person.MatchCode = value
+	case ContractRefIDNo:
+		d.IDNo = value
+	default:
+		d.Memo = value
 	}
 }
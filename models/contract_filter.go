@@ -0,0 +1,84 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// ContractSortField selects the field used to order contract lists
+type ContractSortField string
+
+const (
+	ContractSortByID         ContractSortField = "id"
+	ContractSortByName       ContractSortField = "name"
+	ContractSortByValidFrom  ContractSortField = "validFrom"
+	ContractSortByValidUntil ContractSortField = "validUntil"
+)
+
+// ArchiveFilter selects how archived (soft deleted) contracts are listed
+type ArchiveFilter string
+
+const (
+	ArchivedInclude ArchiveFilter = ""        // List archived and live contracts (default)
+	ArchivedExclude ArchiveFilter = "exclude" // Skip archived contracts
+	ArchivedOnly    ArchiveFilter = "only"    // List archived contracts only
+)
+
+// ContractFilter narrows the result of a contract list
+type ContractFilter struct {
+	NamePrefix     string    // Case-insensitive name prefix
+	NameContains   string    // Case-insensitive name substring
+	FilialID       string    // Exact filial (branch) ID
+	ActiveOn       time.Time // Only contracts valid on this date
+	ExpiringBefore time.Time // Only contracts whose validity ends before this date
+	MinID          int       // Lowest contract ID (inclusive), 0 = no bound
+	MaxID          int       // Highest contract ID (inclusive), 0 = no bound
+
+	// Archived filters on the soft delete flag. The zero value, ArchivedInclude, lists archived
+	// contracts too: set ArchivedExclude to hide them. Without a database, anything but
+	// ArchivedInclude reads the detail of the matching contracts until the page is complete.
+	Archived ArchiveFilter
+
+	SortBy   ContractSortField // Empty keeps the server order
+	SortDesc bool
+	Limit    int // 0 = no limit
+	Offset   int
+}
+
+// Matches reports whether a contract satisfies every criteria of the filter
+func (f ContractFilter) Matches(c ContractList) bool {
+	name := strings.ToLower(c.Name)
+
+	if f.NamePrefix != "" && !strings.HasPrefix(name, strings.ToLower(f.NamePrefix)) {
+		return false
+	}
+
+	if f.NameContains != "" && !strings.Contains(name, strings.ToLower(f.NameContains)) {
+		return false
+	}
+
+	if f.FilialID != "" && c.FilialID != f.FilialID {
+		return false
+	}
+
+	if f.MinID > 0 && c.ID < f.MinID {
+		return false
+	}
+
+	if f.MaxID > 0 && c.ID > f.MaxID {
+		return false
+	}
+
+	if !f.ActiveOn.IsZero() && !IsValidOn(c.ValidFrom, c.ValidUntil, f.ActiveOn) {
+		return false
+	}
+
+	if !f.ExpiringBefore.IsZero() {
+		until, err := ParseDate(c.ValidUntil)
+		if err != nil || !until.Before(f.ExpiringBefore) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,117 @@
+package models
+
+import "strings"
+
+// maxLicensePlates is the number of license plate slots of a participant (lpn1..lpn3)
+const maxLicensePlates = 3
+
+// LicensePlate is a normalized vehicle license plate.
+// ZR slots hold the plate number alone, so the country is not stored with the participant.
+type LicensePlate struct {
+	Country string // Optional country code, e.g. "D", "F", "CH"
+	Number  string
+}
+
+// NormalizePlate builds a license plate, uppercasing the country and number and stripping spaces,
+// dashes and dots
+func NormalizePlate(country, number string) LicensePlate {
+	return LicensePlate{
+		Country: normalizePlateNumber(country),
+		Number:  normalizePlateNumber(number),
+	}
+}
+
+// ParseLicensePlate parses a plate given as "D:AB123" or "AB123"
+func ParseLicensePlate(value string) LicensePlate {
+	if country, number, ok := strings.Cut(value, ":"); ok {
+		return NormalizePlate(country, number)
+	}
+	return NormalizePlate("", value)
+}
+
+// String returns the plate with its country, "D:AB123", or the number alone
+func (p LicensePlate) String() string {
+	if p.Country == "" {
+		return p.Number
+	}
+	return p.Country + ":" + p.Number
+}
+
+// IsZero reports whether the plate is empty
+func (p LicensePlate) IsZero() bool {
+	return p.Number == ""
+}
+
+// Matches reports whether two plates designate the same vehicle, once normalized.
+// Countries are only compared when both plates carry one.
+func (p LicensePlate) Matches(other LicensePlate) bool {
+	if normalizePlateNumber(p.Number) != normalizePlateNumber(other.Number) {
+		return false
+	}
+	a, b := normalizePlateNumber(p.Country), normalizePlateNumber(other.Country)
+	return a == "" || b == "" || a == b
+}
+
+func normalizePlateNumber(number string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '-', '.':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(number))
+}
+
+// PlateDuplicate is a license plate registered on more than one participant
+type PlateDuplicate struct {
+	Plate  LicensePlate
+	Owners []ParticipantOwner
+}
+
+// LicensePlates returns the plates registered on a participant
+func (d *ParticipantDetail) LicensePlates() []LicensePlate {
+	plates := make([]LicensePlate, 0, maxLicensePlates)
+	for _, slot := range d.plateSlots() {
+		if *slot != "" {
+			plates = append(plates, NormalizePlate("", *slot))
+		}
+	}
+	return plates
+}
+
+// HasLicensePlate reports whether the plate is registered on the participant
+func (d *ParticipantDetail) HasLicensePlate(plate LicensePlate) bool {
+	for _, p := range d.LicensePlates() {
+		if p.Matches(plate) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddLicensePlate stores the plate number in the first free slot, returning false when all slots are used
+func (d *ParticipantDetail) AddLicensePlate(plate LicensePlate) bool {
+	for _, slot := range d.plateSlots() {
+		if *slot == "" {
+			*slot = plate.Number
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveLicensePlate clears the slots holding the plate, returning false when it was not registered
+func (d *ParticipantDetail) RemoveLicensePlate(plate LicensePlate) bool {
+	removed := false
+	for _, slot := range d.plateSlots() {
+		if *slot != "" && NormalizePlate("", *slot).Matches(plate) {
+			*slot = ""
+			removed = true
+		}
+	}
+	return removed
+}
+
+func (d *ParticipantDetail) plateSlots() []*string {
+	return []*string{&d.LPN1, &d.LPN2, &d.LPN3}
+}
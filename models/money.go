@@ -0,0 +1,122 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// moneyMinorUnits is the number of decimals of the amounts returned by ZR
+const moneyMinorUnits = 2
+
+// Money is an amount in minor currency units (e.g. cents)
+type Money struct {
+	Amount   MinorUnits `xml:",chardata"`
+	Currency string     `xml:"currency,attr,omitempty"`
+}
+
+// MinorUnits is an amount in minor currency units, exchanged with ZR as a decimal in major units ("12.50")
+type MinorUnits int64
+
+// NewMoney creates an amount in minor currency units
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: MinorUnits(amount), Currency: currency}
+}
+
+// Add returns the sum of two amounts of the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency && m.Currency != "" && other.Currency != "" {
+		return Money{}, fmt.Errorf("cannot add %s to %s", other.Currency, m.Currency)
+	}
+
+	currency := m.Currency
+	if currency == "" {
+		currency = other.Currency
+	}
+
+	return Money{Amount: m.Amount + other.Amount, Currency: currency}, nil
+}
+
+// Decimal formats the amount in major units, e.g. "12.34"
+func (m Money) Decimal() string {
+	return m.Amount.Decimal()
+}
+
+// String formats the amount with its currency, e.g. "12.34 EUR"
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.Currency
+}
+
+// Decimal formats the amount in major units, e.g. "12.34"
+func (u MinorUnits) Decimal() string {
+	sign := ""
+	amount := uint64(u)
+	if u < 0 {
+		sign = "-"
+		amount = -amount // Two's complement magnitude, also right for MinInt64
+	}
+
+	divisor := uint64(minorUnitDivisor())
+
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/divisor, moneyMinorUnits, amount%divisor)
+}
+
+// MarshalText formats the amount in major units
+func (u MinorUnits) MarshalText() ([]byte, error) {
+	return []byte(u.Decimal()), nil
+}
+
+// UnmarshalText parses a decimal amount in major units ("12.50", "12.5", "-3", "") into minor units.
+// Amounts with more significant decimals than minor units are rejected rather than rounded.
+func (u *MinorUnits) UnmarshalText(text []byte) error {
+	value := strings.TrimSpace(string(text))
+	if value == "" {
+		*u = 0
+		return nil
+	}
+
+	negative := strings.HasPrefix(value, "-")
+	digits := strings.TrimLeft(value, "+-")
+
+	whole, fraction, _ := strings.Cut(digits, ".")
+	fraction = strings.TrimRight(fraction, "0")
+	if whole == "" || len(fraction) > moneyMinorUnits || len(value)-len(digits) > 1 {
+		return fmt.Errorf("invalid amount %q", value)
+	}
+	fraction += strings.Repeat("0", moneyMinorUnits-len(fraction))
+
+	major, err := strconv.ParseUint(whole, 10, 63)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q: %w", value, err)
+	}
+	minor, err := strconv.ParseUint(fraction, 10, 63)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q: %w", value, err)
+	}
+
+	divisor := minorUnitDivisor()
+	if major > uint64((math.MaxInt64-int64(minor))/divisor) {
+		return fmt.Errorf("invalid amount %q: out of range", value)
+	}
+
+	amount := int64(major)*divisor + int64(minor)
+	if negative {
+		amount = -amount
+	}
+
+	*u = MinorUnits(amount)
+	return nil
+}
+
+// minorUnitDivisor returns the number of minor units in a major unit
+func minorUnitDivisor() int64 {
+	divisor := int64(1)
+	for range moneyMinorUnits {
+		divisor *= 10
+	}
+	return divisor
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"encoding/xml"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+)
+
+// Participant status values (ParticipantDetail.Status)
+const (
+	ParticipantStatusActive  = 0
+	ParticipantStatusBlocked = 1
+)
+
+// Card status values (Identification.Status)
+const (
+	CardStatusActive  = 0
+	CardStatusBlocked = 1
+)
+
+// PresenceState is the anti-passback presence state of a participant
+type PresenceState int
+
+const (
+	PresenceAbsent  PresenceState = 0 // Outside the facility
+	PresencePresent PresenceState = 1 // Inside the facility
+)
+
+// String returns the presence state name
+func (p PresenceState) String() string {
+	switch p {
+	case PresenceAbsent:
+		return "absent"
+	case PresencePresent:
+		return "present"
+	default:
+		return "unknown"
+	}
+}
+
+// ParticipantDetail represents the complete participant (consumer) detail
+type ParticipantDetail struct {
+	XMLName        xml.Name         `xml:"http://gsph.sub.com/cust/types consumerDetail"`
+	Participant    Participant      `xml:"consumer"`
+	Person         *Person          `xml:"person,omitempty"`
+	Identification *Identification  `xml:"identification,omitempty"` // Current card
+	BlockedCards   []Identification `xml:"blockedCard,omitempty"`    // Cards replaced after a loss, kept blocked
+	LPN1           string           `xml:"lpn1"`                     // License plate slots, always sent so clearing one reaches the server
+	LPN2           string           `xml:"lpn2"`
+	LPN3           string           `xml:"lpn3"`
+	Memo           string           `xml:"memo,omitempty"`
+	Present        PresenceState    `xml:"present"` // Always sent, so resetting to absent (0) reaches the server
+	Status         int              `xml:"status"`  // Always sent, so unblocking (0) reaches the server
+	Delete         int              `xml:"delete"`
+}
+
+// Participant represents the basic participant info
+type Participant struct {
+	Href       string `xml:"href,attr,omitempty"`
+	ID         *int   `xml:"id,omitempty"`
+	ContractID int    `xml:"contractid"`
+	Name       string `xml:"name"`
+	FirstName  string `xml:"firstName,omitempty"`
+	ValidFrom  string `xml:"xValidFrom"`
+	ValidUntil string `xml:"xValidUntil"`
+	FilialID   string `xml:"filialId,omitempty"`
+}
+
+// Identification represents the media (card) identifying a participant
+type Identification struct {
+	ParticipantType    int    `xml:"ptcptType"`
+	CardNo             string `xml:"cardno"`
+	CardClass          int    `xml:"cardclass"`
+	IdentificationType int    `xml:"identificationType"`
+	ValidFrom          string `xml:"validFrom,omitempty"`
+	ValidUntil         string `xml:"validUntil,omitempty"`
+	Status             int    `xml:"status"` // CardStatusActive or CardStatusBlocked
+}
+
+// IsBlocked reports whether the card is blocked
+func (i *Identification) IsBlocked() bool {
+	return i.Status == CardStatusBlocked
+}
+
+// ParticipantOwner identifies a participant and the contract it belongs to
+type ParticipantOwner struct {
+	Contract    ContractList
+	Participant ParticipantDetail
+}
+
+// Participants represents the root XML element containing the participants of a contract
+type Participants struct {
+	XMLName     xml.Name          `xml:"http://gsph.sub.com/cust/types consumers"`
+	Participant []ParticipantList `xml:"consumer"`
+}
+
+// ParticipantList represents a single participant in the list
+type ParticipantList struct {
+	XMLName    xml.Name `xml:"consumer"`
+	ID         int      `xml:"id"`
+	ContractID int      `xml:"contractid"`
+	Name       string   `xml:"name"`
+	FirstName  string   `xml:"firstName"`
+	ValidFrom  string   `xml:"xValidFrom"`
+	ValidUntil string   `xml:"xValidUntil"`
+	FilialID   string   `xml:"filialId"`
+}
+
+// ParticipantRequest for creating or updating a participant.
+// On update, only the fields set are applied to the stored participant.
+type ParticipantRequest struct {
+	ID           *int   // Optional on create - nil if 3rd party should generate
+	ContractID   int    // Required
+	Name         string // Required
+	FirstName    string
+	ValidFrom    string // Required - Format: "2021-01-01"
+	ValidUntil   string // Required - Format: "2021-12-31"
+	CardNumber   string // Media / card number
+	LicensePlate string // "AB123" or "D:AB123", normalized, the number alone is stored
+	TicketClass  int    // Ticket class (card class) ID, 0 = ZR default
+	Status       *int   // ParticipantStatusActive or ParticipantStatusBlocked, nil leaves it unchanged (active on create)
+}
+
+// Validate checks the required fields of the request
+func (r ParticipantRequest) Validate() error {
+	errs := &errors.MultiValidationError{}
+
+	if r.ContractID <= 0 {
+		errs.Add("contractId", "contract ID is required", r.ContractID)
+	}
+	if r.Name == "" {
+		errs.Add("name", "name is required", nil)
+	}
+	if r.ValidFrom == "" {
+		errs.Add("validFrom", "validity start is required", nil)
+	}
+	if r.ValidUntil == "" {
+		errs.Add("validUntil", "validity end is required", nil)
+	}
+
+	return errs.Return()
+}
+
+// ToXML converts ParticipantRequest to ParticipantDetail for XML marshaling
+func (r ParticipantRequest) ToXML() ParticipantDetail {
+	detail := ParticipantDetail{
+		Participant: Participant{
+			ID:         r.ID,
+			ContractID: r.ContractID,
+			Name:       r.Name,
+			FirstName:  r.FirstName,
+			ValidFrom:  r.ValidFrom,
+			ValidUntil: r.ValidUntil,
+		},
+	}
+
+	if r.Status != nil {
+		detail.Status = *r.Status
+	}
+
+	if r.LicensePlate != "" {
+		detail.LPN1 = ParseLicensePlate(r.LicensePlate).Number
+	}
+
+	if r.CardNumber != "" || r.TicketClass != 0 {
+		detail.Identification = &Identification{
+			CardNo:     r.CardNumber,
+			CardClass:  r.TicketClass,
+			ValidFrom:  r.ValidFrom,
+			ValidUntil: r.ValidUntil,
+		}
+	}
+
+	return detail
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// ParticipantFilter narrows the result of a participant list
+type ParticipantFilter struct {
+	ContractID   int            // Participants of this contract, 0 = all contracts
+	NamePrefix   string         // Case-insensitive name prefix
+	FilialID     string         // Exact filial (branch) ID
+	CardNumber   string         // Exact card number
+	LicensePlate string         // Plate in any slot, "AB123" or "D:AB123", see ParseLicensePlate
+	ActiveOn     time.Time      // Only participants valid on this date
+	Present      *PresenceState // Only participants in this presence state, nil = any
+	Archived     ArchiveFilter  // Filters on the soft delete flag
+
+	Limit  int // 0 = no limit
+	Offset int
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParticipantField names a participant attribute for CSV column mapping
+type ParticipantField string
+
+const (
+	ParticipantFieldName         ParticipantField = "name"
+	ParticipantFieldFirstName    ParticipantField = "firstName"
+	ParticipantFieldValidFrom    ParticipantField = "validFrom"
+	ParticipantFieldValidUntil   ParticipantField = "validUntil"
+	ParticipantFieldCardNumber   ParticipantField = "cardNumber"
+	ParticipantFieldLicensePlate ParticipantField = "licensePlate"
+)
+
+// Import row status values
+const (
+	ImportStatusValid   = "valid"   // Validated, not created (dry-run)
+	ImportStatusInvalid = "invalid" // Failed validation
+	ImportStatusCreated = "created"
+	ImportStatusFailed  = "failed"  // Rejected by ZR
+	ImportStatusSkipped = "skipped" // Not attempted (stop-on-error, cancellation or invalid file)
+)
+
+// ParticipantImportOptions configures a CSV participant import
+type ParticipantImportOptions struct {
+	// Columns maps participant fields to CSV header names.
+	// Unmapped fields use the field name itself as header (e.g. "cardNumber").
+	Columns map[ParticipantField]string
+
+	Comma       rune // Field delimiter, 0 = ','
+	DryRun      bool // Validate only, create nothing
+	SkipInvalid bool // Create the valid rows even if some rows are invalid
+	Bulk        BulkOptions
+}
+
+// Column returns the CSV header name mapped to a field
+func (o ParticipantImportOptions) Column(field ParticipantField) string {
+	if name, ok := o.Columns[field]; ok && name != "" {
+		return name
+	}
+	return string(field)
+}
+
+// ParticipantImportRow is the outcome of one CSV row
+type ParticipantImportRow struct {
+	Line    int      // Line number in the source file
+	Record  []string // Original CSV values
+	Request ParticipantRequest
+	Status  string
+	ID      int     // Created participant ID
+	Errors  []error // Validation errors, or the creation error
+}
+
+// ParticipantImportReport holds the row-level results of a CSV import
+type ParticipantImportReport struct {
+	Header  []string
+	Comma   rune // Delimiter used by WriteCSV, 0 = ','
+	Rows    []ParticipantImportRow
+	DryRun  bool
+	Valid   int
+	Invalid int
+	Created int
+	Failed  int
+	Skipped int
+}
+
+// WriteCSV writes the source rows followed by the assigned participant ID, status and errors
+func (r *ParticipantImportReport) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	if r.Comma != 0 {
+		out.Comma = r.Comma
+	}
+
+	header := append(append([]string{}, r.Header...), "participantId", "status", "error")
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range r.Rows {
+		id := ""
+		if row.ID != 0 {
+			id = strconv.Itoa(row.ID)
+		}
+
+		messages := make([]string, 0, len(row.Errors))
+		for _, err := range row.Errors {
+			messages = append(messages, err.Error())
+		}
+
+		record := append(append([]string{}, row.Record...), id, row.Status, strings.Join(messages, "; "))
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
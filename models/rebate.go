@@ -0,0 +1,72 @@
+package models
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// RebateKind is the way a rebate reduces the parking fee
+type RebateKind string
+
+const (
+	RebateKindPercentage RebateKind = "percentage" // Value is a percentage of the fee
+	RebateKindAmount     RebateKind = "amount"     // Value is an amount of money
+	RebateKindTimeCredit RebateKind = "timeCredit" // Value is a number of free minutes
+)
+
+// IsValid reports whether the kind is a known rebate kind
+func (k RebateKind) IsValid() bool {
+	switch k {
+	case RebateKindPercentage, RebateKindAmount, RebateKindTimeCredit:
+		return true
+	}
+	return false
+}
+
+// Rebates represents the root XML element containing multiple rebate definitions
+type Rebates struct {
+	XMLName xml.Name `xml:"http://gsph.sub.com/cust/types rebates"`
+	Rebate  []Rebate `xml:"rebate"`
+}
+
+// Rebate represents a rebate (validation / discount) definition
+type Rebate struct {
+	XMLName    xml.Name   `xml:"rebate"`
+	Href       string     `xml:"href,attr,omitempty"`
+	ID         int        `xml:"id"`
+	Name       string     `xml:"name"`
+	Kind       RebateKind `xml:"kind"`
+	Value      Money      `xml:"value"` // Decimal percentage, amount or minutes, depending on Kind
+	ValidFrom  string     `xml:"xValidFrom,omitempty"`
+	ValidUntil string     `xml:"xValidUntil,omitempty"`
+}
+
+// IsValidOn reports whether the rebate can be used on the given day
+func (r Rebate) IsValidOn(day time.Time) bool {
+	return IsValidOn(r.ValidFrom, r.ValidUntil, day)
+}
+
+// Amount returns the fee reduction of an amount rebate
+func (r Rebate) Amount() Money {
+	if r.Kind != RebateKindAmount {
+		return Money{}
+	}
+	return r.Value
+}
+
+// TimeCredit returns the free parking time of a time credit rebate
+func (r Rebate) TimeCredit() time.Duration {
+	if r.Kind != RebateKindTimeCredit {
+		return 0
+	}
+	return time.Duration(r.Value.Amount) * time.Minute / time.Duration(minorUnitDivisor())
+}
+
+// RebateApplication applies a rebate to a ticket or a contract
+type RebateApplication struct {
+	XMLName    xml.Name `xml:"http://gsph.sub.com/cust/types rebateApplication"`
+	RebateID   int      `xml:"rebateId"`
+	TicketNo   string   `xml:"ticketNo,omitempty"`
+	ContractID int      `xml:"contractId,omitempty"`
+	Applied    string   `xml:"applied,omitempty"` // Date/time set by ZR when the rebate was applied
+}
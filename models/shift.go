@@ -0,0 +1,154 @@
+package models
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ShiftStatus is the state of a cashier shift
+type ShiftStatus string
+
+const (
+	ShiftStatusOpen   ShiftStatus = "open"
+	ShiftStatusClosed ShiftStatus = "closed"
+)
+
+// Shifts represents the root XML element containing multiple shifts
+type Shifts struct {
+	XMLName xml.Name `xml:"http://gsph.sub.com/cust/types shifts"`
+	Shift   []Shift  `xml:"shift"`
+}
+
+// Shift represents a cashier shift on a pay device
+type Shift struct {
+	XMLName    xml.Name    `xml:"shift"`
+	Href       string      `xml:"href,attr,omitempty"`
+	ID         int         `xml:"id"`
+	DeviceID   int         `xml:"deviceId"`
+	DeviceName string      `xml:"deviceName,omitempty"`
+	CashierID  string      `xml:"cashierId,omitempty"`
+	Start      string      `xml:"start"`
+	End        string      `xml:"end,omitempty"`
+	Status     ShiftStatus `xml:"status"`
+	Total      Money       `xml:"total"`
+}
+
+// IsOpen reports whether the shift is still open
+func (s Shift) IsOpen() bool {
+	return s.Status == ShiftStatusOpen
+}
+
+// ShiftDetail represents a shift with its totals per payment type
+type ShiftDetail struct {
+	XMLName  xml.Name       `xml:"http://gsph.sub.com/cust/types shiftDetail"`
+	Shift    Shift          `xml:"shift"`
+	Payments []PaymentTotal `xml:"payments>payment"`
+}
+
+// PaymentTotal is the total collected with one payment type during a shift
+type PaymentTotal struct {
+	PaymentType string `xml:"paymentType"` // cash, card, ...
+	Count       int    `xml:"count"`
+	Amount      Money  `xml:"amount"`
+}
+
+// ShiftFilter narrows a shift list
+type ShiftFilter struct {
+	DeviceID int       // 0 = all devices
+	From     time.Time // Shifts started at or after this time
+	Until    time.Time // Shifts started before this time
+}
+
+// Matches reports whether a shift satisfies the filter.
+// Starts without a zone are read in the location of the filter bounds;
+// a start that cannot be parsed is reported rather than silently left out.
+func (f ShiftFilter) Matches(s Shift) (bool, error) {
+	if f.DeviceID != 0 && s.DeviceID != f.DeviceID {
+		return false, nil
+	}
+
+	if f.From.IsZero() && f.Until.IsZero() {
+		return true, nil
+	}
+
+	start, err := ParseDateIn(s.Start, f.location())
+	if err != nil {
+		return false, fmt.Errorf("shift %d: %w", s.ID, err)
+	}
+
+	if !f.From.IsZero() && start.Before(f.From) {
+		return false, nil
+	}
+
+	return f.Until.IsZero() || start.Before(f.Until), nil
+}
+
+// location returns the location of the filter bounds
+func (f ShiftFilter) location() *time.Location {
+	if !f.From.IsZero() {
+		return f.From.Location()
+	}
+	return f.Until.Location()
+}
+
+// shiftCSVHeader is shared by the shift CSV exports
+var shiftCSVHeader = []string{"shiftId", "deviceId", "deviceName", "cashierId", "start", "end", "status"}
+
+func shiftCSVRecord(s Shift) []string {
+	return []string{
+		strconv.Itoa(s.ID),
+		strconv.Itoa(s.DeviceID),
+		s.DeviceName,
+		s.CashierID,
+		s.Start,
+		s.End,
+		string(s.Status),
+	}
+}
+
+// WriteCSV writes one line per shift with its total, for reconciliation
+func (s *Shifts) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+
+	if err := out.Write(append(append([]string{}, shiftCSVHeader...), "total", "currency")); err != nil {
+		return err
+	}
+
+	for _, shift := range s.Shift {
+		record := append(shiftCSVRecord(shift), shift.Total.Decimal(), shift.Total.Currency)
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// ShiftDetails is a set of shift details
+type ShiftDetails []ShiftDetail
+
+// WriteCSV writes one line per shift and payment type, for reconciliation
+func (d ShiftDetails) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+
+	if err := out.Write(append(append([]string{}, shiftCSVHeader...), "paymentType", "count", "amount", "currency")); err != nil {
+		return err
+	}
+
+	for _, detail := range d {
+		for _, payment := range detail.Payments {
+			record := append(shiftCSVRecord(detail.Shift), payment.PaymentType, strconv.Itoa(payment.Count), payment.Amount.Decimal(), payment.Amount.Currency)
+			if err := out.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,20 @@
+package models
+
+import "encoding/xml"
+
+// TicketClasses represents the root XML element containing multiple ticket classes
+type TicketClasses struct {
+	XMLName     xml.Name      `xml:"http://gsph.sub.com/cust/types ticketClasses"`
+	TicketClass []TicketClass `xml:"ticketClass"`
+}
+
+// TicketClass represents a ticket class (card class) and the tariff it applies
+type TicketClass struct {
+	XMLName    xml.Name `xml:"ticketClass"`
+	Href       string   `xml:"href,attr,omitempty"`
+	ID         int      `xml:"id"`
+	Name       string   `xml:"name"`
+	TariffRef  string   `xml:"tariffRef,omitempty"` // Tariff applied to the class
+	ValidFrom  string   `xml:"xValidFrom,omitempty"`
+	ValidUntil string   `xml:"xValidUntil,omitempty"`
+}
@@ -6,3 +6,30 @@ const (
 	ContractCustomerMediaByID   = "/CustomerMediaWebService/contracts/%d"        // DELETE
 	ContractCustomerMediaDetail = "/CustomerMediaWebService/contracts/%d/detail" // UPDATE
 )
+
+const (
+	// CustomerMedia Participants (consumers) endpoints
+	ParticipantCustomerMedia       = "/CustomerMediaWebService/contracts/%d/consumers"           // GET, POST
+	ParticipantCustomerMediaByID   = "/CustomerMediaWebService/contracts/%d/consumers/%d"        // GET, DELETE
+	ParticipantCustomerMediaDetail = "/CustomerMediaWebService/contracts/%d/consumers/%d/detail" // UPDATE
+)
+
+const (
+	// TicketClass endpoints
+	TicketClassCustomerMedia     = "/CustomerMediaWebService/ticketclasses"    // GET
+	TicketClassCustomerMediaByID = "/CustomerMediaWebService/ticketclasses/%d" // GET
+)
+
+const (
+	// Rebate endpoints
+	RebateCustomerMedia     = "/CustomerMediaWebService/rebates"              // GET
+	RebateCustomerMediaByID = "/CustomerMediaWebService/rebates/%d"           // GET
+	RebateApplyTicket       = "/CustomerMediaWebService/tickets/%s/rebates"   // POST
+	RebateApplyContract     = "/CustomerMediaWebService/contracts/%d/rebates" // POST
+)
+
+const (
+	// Shift endpoints
+	ShiftCustomerMedia       = "/CustomerMediaWebService/shifts"           // GET
+	ShiftCustomerMediaDetail = "/CustomerMediaWebService/shifts/%d/detail" // GET
+)
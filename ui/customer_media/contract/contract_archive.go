@@ -0,0 +1,83 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// Soft delete flag values (ContractDetail.Delete)
+const (
+	deleteFlagLive     = 0
+	deleteFlagArchived = 1
+)
+
+// ArchiveContract soft deletes a contract by setting its deletion flag
+func (s *ContractService) ArchiveContract(ctx context.Context, contractID int) (*models.ContractDetail, error) {
+	return s.setDeleteFlag(ctx, contractID, deleteFlagArchived)
+}
+
+// RestoreContract clears the deletion flag of an archived contract
+func (s *ContractService) RestoreContract(ctx context.Context, contractID int) (*models.ContractDetail, error) {
+	return s.setDeleteFlag(ctx, contractID, deleteFlagLive)
+}
+
+// setDeleteFlag updates the soft delete flag of a contract
+func (s *ContractService) setDeleteFlag(ctx context.Context, contractID int, flag int) (*models.ContractDetail, error) {
+	archive := flag == deleteFlagArchived
+	s.logger.Info("setting contract archive state", logger.Int("contract_id", contractID), logger.Bool("archived", archive))
+
+	detail, err := s.loadContract(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	if detail.IsDeleted() == archive {
+		if archive {
+			return nil, errors.NewValidationError("delete", "contract is already archived", contractID)
+		}
+		return nil, errors.NewValidationError("delete", "contract is not archived", contractID)
+	}
+
+	detail.SetDelete(flag)
+
+	result, err := s.updateContractDetail(ctx, contractID, *detail)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("contract archive state updated", logger.Int("contract_id", contractID), logger.Bool("archived", archive))
+
+	return result, nil
+}
+
+// ArchiveLookup lists the contracts matching a filter, archive criterion, sorting and paging included
+type ArchiveLookup func(ctx context.Context, filter models.ContractFilter) ([]models.ContractList, error)
+
+// filterArchived keeps contracts according to their soft delete flag, read from their detail.
+// Contracts are checked in order and the scan stops once want contracts are kept, 0 = no bound.
+func (s *ContractService) filterArchived(ctx context.Context, contracts []models.ContractList, mode models.ArchiveFilter, want int) ([]models.ContractList, error) {
+	kept := make([]models.ContractList, 0, len(contracts))
+
+	for _, c := range contracts {
+		if want > 0 && len(kept) == want {
+			break
+		}
+
+		detail, err := s.GetContractById(ctx, c.ID)
+		if err != nil {
+			if errors.IsNotFoundError(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if detail.IsDeleted() == (mode == models.ArchivedOnly) {
+			kept = append(kept, c)
+		}
+	}
+
+	return kept, nil
+}
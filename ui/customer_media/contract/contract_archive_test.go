@@ -0,0 +1,135 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+func TestArchiveAndRestoreContract(t *testing.T) {
+	store, svc := newStoreService(t)
+	ctx := context.Background()
+	live := store.Add(models.ContractDetail{Contract: models.Contract{Name: "Live"}, Memo: "kept"})
+	id := store.Add(models.ContractDetail{Contract: models.Contract{Name: "Acme"}, Memo: "kept"})
+
+	if _, err := svc.ArchiveContract(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if d := store.Items[id]; !d.IsDeleted() || d.Memo != "kept" {
+		t.Fatalf("archived contract: deleted %v, memo %q", d.IsDeleted(), d.Memo)
+	}
+	if _, err := svc.ArchiveContract(ctx, id); !errors.IsValidationError(err) {
+		t.Fatalf("archiving twice: got %v", err)
+	}
+
+	for mode, want := range map[models.ArchiveFilter][]int{
+		models.ArchivedInclude: {live, id},
+		models.ArchivedExclude: {live},
+		models.ArchivedOnly:    {id},
+	} {
+		list, err := svc.ListContracts(ctx, models.ContractFilter{Archived: mode})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []int
+		for _, c := range list {
+			got = append(got, c.ID)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("mode %q: got %v, want %v", mode, got, want)
+		}
+	}
+
+	if _, err := svc.RestoreContract(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if d := store.Get(id); d.IsDeleted() {
+		t.Fatal("contract still archived after restore")
+	}
+	if _, err := svc.RestoreContract(ctx, id); !errors.IsValidationError(err) {
+		t.Fatalf("restoring a live contract: got %v", err)
+	}
+}
+
+func TestDeleteContractHardDeleteProtection(t *testing.T) {
+	store, svc := newStoreService(t)
+	ctx := context.Background()
+	id := store.Add(models.ContractDetail{Contract: models.Contract{Name: "Acme"}})
+
+	svc.SetHardDeleteProtection(true)
+	if err := svc.DeleteContract(ctx, id); !errors.IsValidationError(err) {
+		t.Fatalf("protected delete: got %v", err)
+	}
+	if _, ok := store.Items[id]; !ok {
+		t.Fatal("protected contract was deleted")
+	}
+
+	if err := svc.ForceDeleteContract(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Items[id]; ok {
+		t.Fatal("forced delete left the contract")
+	}
+
+	id = store.Add(models.ContractDetail{Contract: models.Contract{Name: "Acme"}})
+	svc.SetHardDeleteProtection(false)
+	if err := svc.DeleteContract(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Items[id]; ok {
+		t.Fatal("unprotected delete left the contract")
+	}
+}
+
+func TestListContractsArchiveFilterStopsAtPage(t *testing.T) {
+	store, svc := newStoreService(t)
+	ctx := context.Background()
+
+	var ids []int
+	for i := range 10 {
+		d := models.ContractDetail{Contract: models.Contract{Name: fmt.Sprintf("C%d", i)}}
+		if i == 1 {
+			d.SetDelete(deleteFlagArchived)
+		}
+		ids = append(ids, store.Add(d))
+	}
+
+	list, err := svc.ListContracts(ctx, models.ContractFilter{Archived: models.ArchivedExclude, Offset: 1, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []int
+	for _, c := range list {
+		got = append(got, c.ID)
+	}
+	if fmt.Sprint(got) != fmt.Sprint([]int{ids[2], ids[3]}) {
+		t.Fatalf("got %v, want %v", got, []int{ids[2], ids[3]})
+	}
+	if store.Details != 4 {
+		t.Fatalf("%d detail requests, want 4", store.Details)
+	}
+}
+
+func TestListContractsUsesArchiveLookup(t *testing.T) {
+	var requests int
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) { requests++ })
+
+	var filters []models.ContractFilter
+	svc.SetArchiveLookup(func(ctx context.Context, filter models.ContractFilter) ([]models.ContractList, error) {
+		filters = append(filters, filter)
+		return []models.ContractList{{ID: 7}}, nil
+	})
+
+	filter := models.ContractFilter{Archived: models.ArchivedOnly, Limit: 10}
+	list, err := svc.ListContracts(context.Background(), filter)
+	if err != nil || len(list) != 1 || list[0].ID != 7 {
+		t.Fatalf("got %v, %v", list, err)
+	}
+	if requests != 0 || len(filters) != 1 || filters[0] != filter {
+		t.Fatalf("%d requests, lookups %v", requests, filters)
+	}
+}
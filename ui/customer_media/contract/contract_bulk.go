@@ -0,0 +1,75 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/bulk"
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// BulkCreate creates contracts with bounded concurrency and reports the result of every item
+func (s *ContractService) BulkCreate(ctx context.Context, reqs []models.ContractRequest, opts models.BulkOptions) (*models.BulkReport, error) {
+	s.logger.Info("bulk creating contracts", logger.Int("count", len(reqs)), logger.Int("concurrency", opts.Concurrency))
+
+	report := bulk.Run(ctx, reqs, opts, func(ctx context.Context, req models.ContractRequest) (int, error) {
+		result, err := s.CreateContract(ctx, req)
+		if err != nil {
+			return 0, err
+		}
+		if result.Contract.ID == nil {
+			return 0, nil
+		}
+		return *result.Contract.ID, nil
+	})
+
+	return s.bulkDone("create", report, ctx.Err())
+}
+
+// BulkUpdate updates contracts with bounded concurrency and reports the result of every item
+func (s *ContractService) BulkUpdate(ctx context.Context, reqs []models.ContractRequest, opts models.BulkOptions) (*models.BulkReport, error) {
+	s.logger.Info("bulk updating contracts", logger.Int("count", len(reqs)), logger.Int("concurrency", opts.Concurrency))
+
+	report := bulk.Run(ctx, reqs, opts, func(ctx context.Context, req models.ContractRequest) (int, error) {
+		if req.ID == nil {
+			return 0, errors.NewValidationError("id", "contract ID is required for update", nil)
+		}
+		if _, err := s.UpdateContract(ctx, req); err != nil {
+			return *req.ID, err
+		}
+		return *req.ID, nil
+	})
+
+	return s.bulkDone("update", report, ctx.Err())
+}
+
+// BulkDelete deletes contracts with bounded concurrency and reports the result of every item
+func (s *ContractService) BulkDelete(ctx context.Context, contractIDs []int, opts models.BulkOptions) (*models.BulkReport, error) {
+	s.logger.Info("bulk deleting contracts", logger.Int("count", len(contractIDs)), logger.Int("concurrency", opts.Concurrency))
+
+	report := bulk.Run(ctx, contractIDs, opts, func(ctx context.Context, contractID int) (int, error) {
+		return contractID, s.DeleteContract(ctx, contractID)
+	})
+
+	return s.bulkDone("delete", report, ctx.Err())
+}
+
+// bulkDone logs the outcome of a bulk operation
+func (s *ContractService) bulkDone(operation string, report *models.BulkReport, ctxErr error) (*models.BulkReport, error) {
+	fields := []logger.Field{
+		logger.String("operation", operation),
+		logger.Int("succeeded", report.Succeeded),
+		logger.Int("failed", report.Failed),
+		logger.Int("skipped", report.Skipped),
+	}
+
+	if ctxErr != nil {
+		s.logger.Warn("bulk contract operation cancelled", append(fields, logger.Error(ctxErr))...)
+		return report, ctxErr
+	}
+
+	s.logger.Info("bulk contract operation finished", fields...)
+
+	return report, nil
+}
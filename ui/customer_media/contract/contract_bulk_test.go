@@ -0,0 +1,43 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+func TestBulkDeleteReportsPerItem(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		deleted []string
+	)
+
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == fmt.Sprintf(models.ContractCustomerMediaByID, 2) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		deleted = append(deleted, r.URL.Path)
+		mu.Unlock()
+	})
+
+	report, err := svc.BulkDelete(context.Background(), []int{1, 2, 3}, models.BulkOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatalf("BulkDelete: %v", err)
+	}
+
+	if report.Succeeded != 2 || report.Failed != 1 {
+		t.Fatalf("report %+v", report)
+	}
+	if res := report.Results[1]; res.ID != 2 || res.Err == nil {
+		t.Fatalf("result of contract 2: %+v", res)
+	}
+	if len(deleted) != 2 {
+		t.Fatalf("deleted %v", deleted)
+	}
+}
@@ -0,0 +1,208 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// idempotentCreateAttempts is the number of POST attempts made for an idempotent creation
+const idempotentCreateAttempts = 3
+
+// createIdempotent creates a contract at most once per idempotency key.
+// The key is looked up before the first attempt and after every retryable failure,
+// since a timed-out POST may still have been committed by ZR.
+func (s *ContractService) createIdempotent(ctx context.Context, req models.ContractRequest) (*models.ContractDetail, error) {
+	unlock := s.keyLocks.lock(req.IdempotencyKey)
+	defer unlock()
+
+	field := s.referenceField()
+
+	existing, err := s.findByReference(ctx, field, req.IdempotencyKey, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		s.logger.Info("contract already exists for idempotency key", logger.String("key", req.IdempotencyKey), logger.Int("contract_id", *existing.Contract.ID))
+		return existing, nil
+	}
+
+	contractDetail := req.ToXML()
+	field.Set(&contractDetail, req.IdempotencyKey)
+
+	for attempt := 1; ; attempt++ {
+		result, err := s.createContract(ctx, contractDetail)
+		if err == nil {
+			return result, nil
+		}
+
+		if !errors.IsRetryable(err) || attempt >= idempotentCreateAttempts || ctx.Err() != nil {
+			return nil, err
+		}
+
+		s.logger.Warn("retrying idempotent contract creation", logger.String("key", req.IdempotencyKey), logger.Int("attempt", attempt), logger.Error(err))
+
+		existing, findErr := s.findByReference(ctx, field, req.IdempotencyKey, req.Name)
+		if findErr != nil {
+			return nil, findErr
+		}
+		if existing != nil {
+			s.logger.Info("contract committed by a previous attempt", logger.String("key", req.IdempotencyKey), logger.Int("contract_id", *existing.Contract.ID))
+			return existing, nil
+		}
+	}
+}
+
+// defaultReferenceScanLimit bounds the contract details fetched by a reference scan
+const defaultReferenceScanLimit = 1000
+
+// ReferenceLookup returns the IDs of the contracts whose reference field may hold ref.
+// ok is false when the lookup cannot answer for field. Candidates are always verified
+// against the ZR web service.
+type ReferenceLookup func(ctx context.Context, field models.ContractRefField, ref string) (ids []int, ok bool, err error)
+
+// checkReferenceConflict rejects a request setting the memo when the memo holds its reference,
+// the memo would be overwritten by the reference
+func checkReferenceConflict(field models.ContractRefField, req models.ContractRequest) error {
+	if field == models.ContractRefMemo && req.Memo != "" {
+		return errors.NewValidationError("memo", "memo holds the contract reference, select another reference field to set a memo", nil)
+	}
+	return nil
+}
+
+// findByReference returns the contract whose reference field equals ref, or nil.
+//
+// Candidates come from the ReferenceLookup when one is set and supports field. Otherwise the
+// contract list is scanned once per name, "" matching every contract, and the detail of each
+// candidate not inspected yet is fetched: one request per candidate, at most the scan limit
+// in total before failing.
+func (s *ContractService) findByReference(ctx context.Context, field models.ContractRefField, ref string, names ...string) (*models.ContractDetail, error) {
+	s.mu.RLock()
+	lookup, limit := s.refLookup, s.refScanLimit
+	s.mu.RUnlock()
+
+	if lookup != nil {
+		ids, ok, err := lookup(ctx, field, ref)
+		switch {
+		case err != nil:
+			s.logger.Warn("reference lookup failed, scanning contracts", logger.String("field", string(field)), logger.Error(err))
+		case ok:
+			return s.matchReference(ctx, field, ref, slices.Values(ids))
+		}
+	}
+
+	if limit == 0 {
+		limit = defaultReferenceScanLimit
+	}
+
+	fetched := 0
+	seen := make(map[int]bool)
+
+	for _, name := range names {
+		var scanErr error
+
+		candidates := func(yield func(int) bool) {
+			for c, err := range s.IterContracts(ctx, models.PageOptions{}) {
+				if err != nil {
+					scanErr = err
+					return
+				}
+				if seen[c.ID] || (name != "" && !strings.EqualFold(c.Name, name)) {
+					continue
+				}
+				if limit > 0 && fetched >= limit {
+					scanErr = errors.NewSDKError(errors.ErrorTypeInternal,
+						fmt.Sprintf("contract reference scan exceeded %d contracts, configure the database or raise the scan limit", limit), nil)
+					return
+				}
+				fetched++
+				seen[c.ID] = true
+				if !yield(c.ID) {
+					return
+				}
+			}
+		}
+
+		detail, err := s.matchReference(ctx, field, ref, candidates)
+		if err != nil {
+			return nil, err
+		}
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		if detail != nil {
+			return detail, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// matchReference fetches the detail of every candidate until one holds ref
+func (s *ContractService) matchReference(ctx context.Context, field models.ContractRefField, ref string, candidates iter.Seq[int]) (*models.ContractDetail, error) {
+	for contractID := range candidates {
+		detail, err := s.GetContractById(ctx, contractID)
+		if err != nil {
+			if errors.IsNotFoundError(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if field.Get(detail) == ref {
+			if detail.Contract.ID == nil {
+				id := contractID
+				detail.Contract.ID = &id
+			}
+			return detail, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// keyLocks provides one mutex per key, released once no caller holds it
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lock acquires the mutex for key and returns its release function
+func (k *keyLocks) lock(key string) func() {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyLock)
+	}
+	l, ok := k.locks[key]
+	if !ok {
+		l = &keyLock{}
+		k.locks[key] = l
+	}
+	l.refs++
+	k.mu.Unlock()
+
+	l.mu.Lock()
+
+	return func() {
+		l.mu.Unlock()
+
+		k.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
@@ -0,0 +1,124 @@
+package contract
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/testutil"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+func newStoreService(t *testing.T) (*testutil.ContractStore, *ContractService) {
+	store := testutil.NewContractStore()
+	return store, newTestService(t, store.ServeHTTP)
+}
+
+func TestCreateContractIdempotent(t *testing.T) {
+	store, svc := newStoreService(t)
+	store.Add(models.ContractDetail{Contract: models.Contract{Name: "Other"}, Memo: "crm-2"})
+
+	req := models.ContractRequest{Name: "Acme", ValidFrom: "2024-01-01", ValidUntil: "2025-01-01", IdempotencyKey: "crm-1"}
+
+	var wg sync.WaitGroup
+	ids := make([]int, 3)
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d, err := svc.CreateContract(context.Background(), req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ids[i] = *d.Contract.ID
+		}()
+	}
+	wg.Wait()
+
+	if store.Posts != 1 {
+		t.Fatalf("%d contracts created, want 1", store.Posts)
+	}
+	if ids[0] != ids[1] || ids[1] != ids[2] {
+		t.Fatalf("callers got different contracts %v", ids)
+	}
+	if memo := store.Items[ids[0]].Memo; memo != "crm-1" {
+		t.Fatalf("idempotency key not stored, memo %q", memo)
+	}
+}
+
+func TestCreateContractIdempotentRetryFindsCommittedContract(t *testing.T) {
+	store, svc := newStoreService(t)
+	store.FailPost = 1
+
+	d, err := svc.CreateContract(context.Background(), models.ContractRequest{Name: "Acme", IdempotencyKey: "crm-1"})
+	if err != nil {
+		t.Fatalf("CreateContract: %v", err)
+	}
+	if store.Posts != 1 || *d.Contract.ID != 100 {
+		t.Fatalf("posts %d, contract %d", store.Posts, *d.Contract.ID)
+	}
+}
+
+func TestCreateContractIdempotentRejectsMemo(t *testing.T) {
+	store, svc := newStoreService(t)
+
+	_, err := svc.CreateContract(context.Background(), models.ContractRequest{Name: "Acme", Memo: "note", IdempotencyKey: "crm-1"})
+	if !errors.IsValidationError(err) {
+		t.Fatalf("got %v, want a validation error", err)
+	}
+	if store.Posts != 0 {
+		t.Fatal("contract created")
+	}
+
+	// With another reference field the memo is kept
+	svc.SetReferenceField(models.ContractRefIDNo)
+	d, err := svc.CreateContract(context.Background(), models.ContractRequest{Name: "Acme", Memo: "note", IdempotencyKey: "crm-1"})
+	if err != nil {
+		t.Fatalf("CreateContract: %v", err)
+	}
+	if d.Memo != "note" || d.IDNo != "crm-1" {
+		t.Fatalf("memo %q, idNo %q", d.Memo, d.IDNo)
+	}
+}
+
+func TestFindByReferenceUsesLookup(t *testing.T) {
+	store, svc := newStoreService(t)
+	for i := 0; i < 20; i++ {
+		store.Add(models.ContractDetail{Contract: models.Contract{Name: "Acme"}})
+	}
+	want := store.Add(models.ContractDetail{Contract: models.Contract{Name: "Acme"}, Memo: "crm-1"})
+
+	svc.SetReferenceLookup(func(ctx context.Context, field models.ContractRefField, ref string) ([]int, bool, error) {
+		return []int{want}, true, nil
+	})
+
+	d, err := svc.CreateContract(context.Background(), models.ContractRequest{Name: "Acme", IdempotencyKey: "crm-1"})
+	if err != nil {
+		t.Fatalf("CreateContract: %v", err)
+	}
+	if *d.Contract.ID != want || store.Posts != 0 {
+		t.Fatalf("got contract %d, %d created", *d.Contract.ID, store.Posts)
+	}
+	if store.Details != 1 {
+		t.Fatalf("%d detail requests, want 1", store.Details)
+	}
+}
+
+func TestFindByReferenceScanLimit(t *testing.T) {
+	store, svc := newStoreService(t)
+	for i := 0; i < 5; i++ {
+		store.Add(models.ContractDetail{Contract: models.Contract{Name: "Acme"}})
+	}
+	svc.SetReferenceScanLimit(3)
+
+	_, err := svc.CreateContract(context.Background(), models.ContractRequest{Name: "Acme", IdempotencyKey: "crm-1"})
+	if err == nil || !strings.Contains(err.Error(), "scan exceeded 3") {
+		t.Fatalf("got %v, want a scan limit error", err)
+	}
+	if store.Posts != 0 || store.Details != 3 {
+		t.Fatalf("posts %d, detail requests %d", store.Posts, store.Details)
+	}
+}
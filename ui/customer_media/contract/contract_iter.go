@@ -0,0 +1,50 @@
+package contract
+
+import (
+	"context"
+	"encoding/xml"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	internalhttp "github.com/yassine-manai/go_zr_sdk/internal/http"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/internal/paging"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// IterContracts returns a sequence over every contract, fetching pages on demand.
+// Breaking out of the loop stops fetching. On ZR versions without paging the full
+// response is streamed and decoded one contract at a time.
+// The request timeout only bounds the wait for each page, not the time spent in the loop body.
+func (s *ContractService) IterContracts(ctx context.Context, opts models.PageOptions) iter.Seq2[models.ContractList, error] {
+	key := func(c models.ContractList) int { return c.ID }
+
+	return paging.Iterate(ctx, opts.PageSize, key, s.fetchContractPage)
+}
+
+// fetchContractPage streams one page of the contract list
+func (s *ContractService) fetchContractPage(ctx context.Context, offset, limit int, yield func(models.ContractList) bool) (int, error) {
+	s.logger.Debug("fetching contract page", logger.Int("offset", offset), logger.Int("limit", limit))
+
+	query := url.Values{}
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("limit", strconv.Itoa(limit))
+	path := models.ContractCustomerMedia + "?" + query.Encode()
+
+	var count int
+
+	err := s.httpClient.DoXMLStream(ctx, http.MethodGet, path, nil, func(dec *xml.Decoder) error {
+		n, err := internalhttp.DecodeEach(dec, "contract", yield)
+		count = n
+		return err
+	})
+
+	if err != nil {
+		s.logger.Error("failed to fetch contract page", logger.Int("offset", offset), logger.Error(err))
+		return count, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,31 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+func TestIterContracts(t *testing.T) {
+	for _, paged := range []bool{true, false} {
+		for _, count := range []int{0, 5, 100, 250} {
+			svc := newTestService(t, contractListHandler(count, paged, nil))
+
+			n := 0
+			for c, err := range svc.IterContracts(context.Background(), models.PageOptions{PageSize: 100}) {
+				if err != nil {
+					t.Fatalf("paged=%v count=%d: %v", paged, count, err)
+				}
+				if c.ID != n+1 {
+					t.Fatalf("paged=%v count=%d: contract %d has ID %d", paged, count, n, c.ID)
+				}
+				n++
+			}
+
+			if n != count {
+				t.Errorf("paged=%v: got %d contracts, want %d", paged, n, count)
+			}
+		}
+	}
+}
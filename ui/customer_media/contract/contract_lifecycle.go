@@ -0,0 +1,255 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// Lifecycle audit events
+const (
+	auditBlock     = "contract.block"
+	auditUnblock   = "contract.unblock"
+	auditExtend    = "contract.extend"
+	auditRenew     = "contract.renew"
+	auditTerminate = "contract.terminate"
+)
+
+// BlockContract blocks an active contract
+func (s *ContractService) BlockContract(ctx context.Context, contractID int, reason string) (*models.ContractDetail, error) {
+	detail, err := s.loadContract(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkModifiable(detail); err != nil {
+		return nil, err
+	}
+	if detail.IsBlocked() {
+		return nil, errors.NewValidationError("status", "contract is already blocked", contractID)
+	}
+
+	previous := detail.StatusValue()
+	detail.SetStatus(models.ContractStatusBlocked)
+
+	result, err := s.updateContractDetail(ctx, contractID, *detail)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(auditBlock, contractID, logger.String("reason", reason),
+		logger.Int("from_status", previous), logger.Int("to_status", models.ContractStatusBlocked))
+
+	return result, nil
+}
+
+// UnblockContract reactivates a blocked contract
+func (s *ContractService) UnblockContract(ctx context.Context, contractID int, reason string) (*models.ContractDetail, error) {
+	detail, err := s.loadContract(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkModifiable(detail); err != nil {
+		return nil, err
+	}
+	if !detail.IsBlocked() {
+		return nil, errors.NewValidationError("status", "contract is not blocked", contractID)
+	}
+
+	previous := detail.StatusValue()
+	detail.SetStatus(models.ContractStatusActive)
+
+	result, err := s.updateContractDetail(ctx, contractID, *detail)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(auditUnblock, contractID, logger.String("reason", reason),
+		logger.Int("from_status", previous), logger.Int("to_status", models.ContractStatusActive))
+
+	return result, nil
+}
+
+// ExtendContract moves the end of validity of a contract by the given period
+func (s *ContractService) ExtendContract(ctx context.Context, contractID int, by models.Period) (*models.ContractDetail, error) {
+	if by.IsZero() {
+		return nil, errors.NewValidationError("by", "extension period is required", nil)
+	}
+
+	detail, err := s.loadContract(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	until, err := models.ParseDate(detail.Contract.ValidUntil)
+	if err != nil {
+		return nil, errors.NewValidationError("validUntil", "contract has no valid end date", detail.Contract.ValidUntil)
+	}
+
+	return s.extendTo(ctx, detail, by.AddTo(until))
+}
+
+// ExtendContractUntil moves the end of validity of a contract to the given date
+func (s *ContractService) ExtendContractUntil(ctx context.Context, contractID int, until time.Time) (*models.ContractDetail, error) {
+	detail, err := s.loadContract(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.extendTo(ctx, detail, until)
+}
+
+// extendTo validates and applies a new, later end of validity
+func (s *ContractService) extendTo(ctx context.Context, detail *models.ContractDetail, until time.Time) (*models.ContractDetail, error) {
+	contractID := *detail.Contract.ID
+
+	if err := checkModifiable(detail); err != nil {
+		return nil, err
+	}
+
+	previous := detail.Contract.ValidUntil
+	if current, err := models.ParseDate(previous); err == nil && !until.After(current) {
+		return nil, errors.NewValidationError("validUntil", "extension must end after the current validity", models.FormatDate(until))
+	}
+
+	detail.Contract.ValidUntil = models.FormatDate(until)
+
+	result, err := s.updateContractDetail(ctx, contractID, *detail)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(auditExtend, contractID,
+		logger.String("from_valid_until", previous), logger.String("to_valid_until", detail.Contract.ValidUntil))
+
+	return result, nil
+}
+
+// RenewContract creates the successor of a contract for another period, starting the day after it ends.
+// Attributes, person, address and branch are carried over to the new contract.
+func (s *ContractService) RenewContract(ctx context.Context, contractID int, period models.Period) (*models.ContractDetail, error) {
+	if period.IsZero() {
+		return nil, errors.NewValidationError("period", "renewal period is required", nil)
+	}
+
+	detail, err := s.loadContract(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkModifiable(detail); err != nil {
+		return nil, err
+	}
+	if detail.IsBlocked() {
+		return nil, errors.NewValidationError("status", "blocked contracts cannot be renewed", contractID)
+	}
+
+	until, err := models.ParseDate(detail.Contract.ValidUntil)
+	if err != nil {
+		return nil, errors.NewValidationError("validUntil", "contract has no valid end date", detail.Contract.ValidUntil)
+	}
+
+	from := until.AddDate(0, 0, 1)
+
+	successor := models.ContractDetail{
+		Contract: models.Contract{
+			Name:       detail.Contract.Name,
+			ValidFrom:  models.FormatDate(from),
+			ValidUntil: models.FormatDate(period.AddTo(from).AddDate(0, 0, -1)),
+			FilialID:   detail.Contract.FilialID,
+			StdAddr:    detail.Contract.StdAddr,
+		},
+		ContractAttributes: detail.ContractAttributes,
+		Person:             detail.Person,
+		StdAddr:            detail.StdAddr,
+		InvoiceGroup:       detail.InvoiceGroup,
+		TaxIDNo:            detail.TaxIDNo,
+		IDNo:               detail.IDNo,
+	}
+
+	result, err := s.createContract(ctx, successor)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []logger.Field{
+		logger.String("valid_from", successor.Contract.ValidFrom),
+		logger.String("valid_until", successor.Contract.ValidUntil),
+	}
+	if result.Contract.ID != nil {
+		fields = append(fields, logger.Int("successor_id", *result.Contract.ID))
+	}
+	s.audit(auditRenew, contractID, fields...)
+
+	return result, nil
+}
+
+// TerminateContract ends the validity of a contract on a future date
+func (s *ContractService) TerminateContract(ctx context.Context, contractID int, on time.Time, reason string) (*models.ContractDetail, error) {
+	today := time.Now()
+	if models.FormatDate(on) <= models.FormatDate(today) {
+		return nil, errors.NewValidationError("on", "termination date must be in the future", models.FormatDate(on))
+	}
+
+	detail, err := s.loadContract(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkModifiable(detail); err != nil {
+		return nil, err
+	}
+
+	previous := detail.Contract.ValidUntil
+	if current, err := models.ParseDate(previous); err == nil && !on.Before(current) {
+		return nil, errors.NewValidationError("on", "termination date must be before the current end of validity", models.FormatDate(on))
+	}
+
+	detail.Contract.ValidUntil = models.FormatDate(on)
+
+	result, err := s.updateContractDetail(ctx, contractID, *detail)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(auditTerminate, contractID, logger.String("reason", reason),
+		logger.String("from_valid_until", previous), logger.String("to_valid_until", detail.Contract.ValidUntil))
+
+	return result, nil
+}
+
+// loadContract fetches a contract detail, making sure its ID is set
+func (s *ContractService) loadContract(ctx context.Context, contractID int) (*models.ContractDetail, error) {
+	detail, err := s.GetContractById(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	if detail.Contract.ID == nil {
+		detail.Contract.ID = &contractID
+	}
+
+	return detail, nil
+}
+
+// checkModifiable rejects lifecycle changes on deleted contracts
+func checkModifiable(detail *models.ContractDetail) error {
+	if detail.IsDeleted() {
+		return errors.NewValidationError("delete", "contract is deleted", *detail.Contract.ID)
+	}
+	return nil
+}
+
+// audit logs a structured contract lifecycle event
+func (s *ContractService) audit(event string, contractID int, fields ...logger.Field) {
+	all := append([]logger.Field{
+		logger.String("event", event),
+		logger.Int("contract_id", contractID),
+	}, fields...)
+
+	s.logger.Info("contract audit event", all...)
+}
@@ -0,0 +1,276 @@
+package contract
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/internal/testutil"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// newTestService returns a ContractService talking to handler
+func newTestService(t *testing.T, handler http.HandlerFunc) *ContractService {
+	return NewContractService(testutil.NewHTTPClient(t, handler), logger.NewNoOpLogger())
+}
+
+// auditLogger records the fields of the contract audit events
+type auditLogger struct {
+	logger.NoOpLogger
+
+	mu     sync.Mutex
+	events []map[string]any
+}
+
+func (l *auditLogger) Info(msg string, fields ...logger.Field) {
+	if msg != "contract audit event" {
+		return
+	}
+	event := map[string]any{}
+	for _, f := range fields {
+		event[f.Key] = f.Value
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *auditLogger) last() map[string]any {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.events) == 0 {
+		return nil
+	}
+	return l.events[len(l.events)-1]
+}
+
+// newLifecycleService returns a ContractService over an in-memory store, auditing to the returned logger
+func newLifecycleService(t *testing.T) (*testutil.ContractStore, *ContractService, *auditLogger) {
+	store := testutil.NewContractStore()
+	audit := &auditLogger{}
+	return store, NewContractService(testutil.NewHTTPClient(t, store.ServeHTTP), audit), audit
+}
+
+func contractDetail(validUntil string) models.ContractDetail {
+	return models.ContractDetail{
+		Contract: models.Contract{Name: "Acme", ValidFrom: "2026-01-01", ValidUntil: validUntil, FilialID: "F1"},
+		Memo:     "crm-1",
+	}
+}
+
+func newContract(store *testutil.ContractStore, validUntil string) int {
+	return store.Add(contractDetail(validUntil))
+}
+
+func archived(d models.ContractDetail) models.ContractDetail {
+	d.SetDelete(deleteFlagArchived)
+	return d
+}
+
+func TestUnblockContractSendsActiveStatus(t *testing.T) {
+	var sent string
+
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+			sent = string(body)
+		}
+		io.WriteString(w, `<contractDetail xmlns="http://gsph.sub.com/cust/types">`+
+			`<contract><id>7</id><name>Acme</name></contract><status>1</status></contractDetail>`)
+	})
+
+	if _, err := svc.UnblockContract(context.Background(), 7, "paid"); err != nil {
+		t.Fatalf("UnblockContract: %v", err)
+	}
+
+	if !strings.Contains(sent, "<status>0</status>") {
+		t.Fatalf("unblock request does not carry the active status:\n%s", sent)
+	}
+}
+
+func TestUpdateContractLeavesStatusAndDeleteAlone(t *testing.T) {
+	var sent []string
+
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sent = append(sent, string(body))
+		io.WriteString(w, `<contractDetail xmlns="http://gsph.sub.com/cust/types"><contract><id>7</id><name>Acme</name></contract></contractDetail>`)
+	})
+
+	id := 7
+	req := models.ContractRequest{ID: &id, Name: "Acme", ValidFrom: "2026-01-01", ValidUntil: "2026-12-31"}
+	if _, err := svc.UpdateContract(context.Background(), req); err != nil {
+		t.Fatalf("UpdateContract: %v", err)
+	}
+	if _, err := svc.CreateContract(context.Background(), req); err != nil {
+		t.Fatalf("CreateContract: %v", err)
+	}
+
+	for _, body := range sent {
+		if strings.Contains(body, "<status>") || strings.Contains(body, "<delete>") {
+			t.Fatalf("request built from a ContractRequest carries status or delete:\n%s", body)
+		}
+	}
+}
+
+func TestBlockContract(t *testing.T) {
+	store, svc, audit := newLifecycleService(t)
+	ctx := context.Background()
+	id := newContract(store, "2026-12-31")
+
+	if _, err := svc.BlockContract(ctx, id, "unpaid"); err != nil {
+		t.Fatal(err)
+	}
+	if d := store.Get(id); !d.IsBlocked() || d.Memo != "crm-1" {
+		t.Fatalf("blocked contract: status %d, memo %q", d.StatusValue(), d.Memo)
+	}
+	if e := audit.last(); e["event"] != auditBlock || e["from_status"] != models.ContractStatusActive || e["to_status"] != models.ContractStatusBlocked {
+		t.Fatalf("audit event %v", e)
+	}
+
+	if _, err := svc.BlockContract(ctx, id, "unpaid"); !errors.IsValidationError(err) {
+		t.Fatalf("blocking twice: got %v", err)
+	}
+
+	gone := store.Add(archived(contractDetail("2026-12-31")))
+	if _, err := svc.BlockContract(ctx, gone, "unpaid"); !errors.IsValidationError(err) {
+		t.Fatalf("blocking an archived contract: got %v", err)
+	}
+
+	if _, err := svc.UnblockContract(ctx, id, "paid"); err != nil {
+		t.Fatal(err)
+	}
+	if d := store.Get(id); d.IsBlocked() {
+		t.Fatal("contract still blocked after unblock")
+	}
+	if e := audit.last(); e["event"] != auditUnblock || e["from_status"] != models.ContractStatusBlocked {
+		t.Fatalf("audit event %v", e)
+	}
+	if _, err := svc.UnblockContract(ctx, id, "paid"); !errors.IsValidationError(err) {
+		t.Fatalf("unblocking an active contract: got %v", err)
+	}
+}
+
+func TestExtendContract(t *testing.T) {
+	store, svc, audit := newLifecycleService(t)
+	ctx := context.Background()
+	id := newContract(store, "2026-12-31")
+
+	if _, err := svc.ExtendContract(ctx, id, models.Period{Months: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.Get(id).Contract.ValidUntil; got != "2027-01-31" {
+		t.Fatalf("valid until %q, want 2027-01-31", got)
+	}
+	if e := audit.last(); e["event"] != auditExtend || e["from_valid_until"] != "2026-12-31" || e["to_valid_until"] != "2027-01-31" {
+		t.Fatalf("audit event %v", e)
+	}
+
+	if _, err := svc.ExtendContract(ctx, id, models.Period{}); !errors.IsValidationError(err) {
+		t.Fatalf("empty extension: got %v", err)
+	}
+
+	noEnd := newContract(store, "")
+	if _, err := svc.ExtendContract(ctx, noEnd, models.Period{Months: 1}); !errors.IsValidationError(err) {
+		t.Fatalf("extending a contract without end date: got %v", err)
+	}
+}
+
+func TestExtendContractUntil(t *testing.T) {
+	store, svc, _ := newLifecycleService(t)
+	ctx := context.Background()
+	id := newContract(store, "2026-12-31")
+
+	until := time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)
+	if _, err := svc.ExtendContractUntil(ctx, id, until); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.Get(id).Contract.ValidUntil; got != "2027-06-30" {
+		t.Fatalf("valid until %q, want 2027-06-30", got)
+	}
+
+	earlier := time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)
+	if _, err := svc.ExtendContractUntil(ctx, id, earlier); !errors.IsValidationError(err) {
+		t.Fatalf("shortening through an extension: got %v", err)
+	}
+	if got := store.Get(id).Contract.ValidUntil; got != "2027-06-30" {
+		t.Fatalf("rejected extension changed validity to %q", got)
+	}
+
+	gone := store.Add(archived(contractDetail("2026-12-31")))
+	if _, err := svc.ExtendContractUntil(ctx, gone, until); !errors.IsValidationError(err) {
+		t.Fatalf("extending an archived contract: got %v", err)
+	}
+}
+
+func TestRenewContract(t *testing.T) {
+	store, svc, audit := newLifecycleService(t)
+	ctx := context.Background()
+	id := newContract(store, "2026-12-31")
+
+	successor, err := svc.RenewContract(ctx, id, models.Period{Years: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := store.Get(*successor.Contract.ID)
+	if next.Contract.ValidFrom != "2027-01-01" || next.Contract.ValidUntil != "2027-12-31" || next.Contract.FilialID != "F1" {
+		t.Fatalf("successor %+v", next.Contract)
+	}
+	if next.Memo != "" {
+		t.Fatalf("successor copies the external reference %q", next.Memo)
+	}
+	if e := audit.last(); e["event"] != auditRenew || e["successor_id"] != *successor.Contract.ID {
+		t.Fatalf("audit event %v", e)
+	}
+
+	if _, err := svc.RenewContract(ctx, id, models.Period{}); !errors.IsValidationError(err) {
+		t.Fatalf("empty renewal: got %v", err)
+	}
+
+	blocked := contractDetail("2026-12-31")
+	blocked.SetStatus(models.ContractStatusBlocked)
+	if _, err := svc.RenewContract(ctx, store.Add(blocked), models.Period{Years: 1}); !errors.IsValidationError(err) {
+		t.Fatalf("renewing a blocked contract: got %v", err)
+	}
+
+	gone := store.Add(archived(contractDetail("2026-12-31")))
+	if _, err := svc.RenewContract(ctx, gone, models.Period{Years: 1}); !errors.IsValidationError(err) {
+		t.Fatalf("renewing an archived contract: got %v", err)
+	}
+}
+
+func TestTerminateContract(t *testing.T) {
+	store, svc, audit := newLifecycleService(t)
+	ctx := context.Background()
+	id := newContract(store, "2099-12-31")
+
+	on := time.Now().AddDate(0, 1, 0)
+	if _, err := svc.TerminateContract(ctx, id, on, "moved"); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.Get(id).Contract.ValidUntil; got != models.FormatDate(on) {
+		t.Fatalf("valid until %q, want %q", got, models.FormatDate(on))
+	}
+	if e := audit.last(); e["event"] != auditTerminate || e["from_valid_until"] != "2099-12-31" || e["reason"] != "moved" {
+		t.Fatalf("audit event %v", e)
+	}
+
+	if _, err := svc.TerminateContract(ctx, id, time.Now(), "moved"); !errors.IsValidationError(err) {
+		t.Fatalf("terminating today: got %v", err)
+	}
+	if _, err := svc.TerminateContract(ctx, id, on.AddDate(0, 1, 0), "moved"); !errors.IsValidationError(err) {
+		t.Fatalf("terminating after the end of validity: got %v", err)
+	}
+
+	gone := store.Add(archived(contractDetail("2099-12-31")))
+	if _, err := svc.TerminateContract(ctx, gone, on, "moved"); !errors.IsValidationError(err) {
+		t.Fatalf("terminating an archived contract: got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package contract
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// ListContracts retrieves the contracts matching the filter.
+// Criteria supported by the ZR API are sent as query parameters, the rest are applied client-side.
+// Archived contracts are listed unless filter.Archived excludes them. An archive filter is answered
+// by the ArchiveLookup when one is set, otherwise by reading the detail of the matching contracts,
+// in order, until the requested page is complete.
+func (s *ContractService) ListContracts(ctx context.Context, filter models.ContractFilter) ([]models.ContractList, error) {
+	s.logger.Info("listing contracts",
+		logger.String("name_prefix", filter.NamePrefix),
+		logger.String("name_contains", filter.NameContains),
+		logger.String("filial_id", filter.FilialID),
+	)
+
+	if filter.Archived != models.ArchivedInclude {
+		s.mu.RLock()
+		lookup := s.archiveLookup
+		s.mu.RUnlock()
+
+		if lookup != nil {
+			contracts, err := lookup(ctx, filter)
+			if err != nil {
+				s.logger.Error("failed to list contracts", logger.Error(err))
+				return nil, err
+			}
+
+			s.logger.Info("contracts listed successfully", logger.Int("Count", len(contracts)))
+			return contracts, nil
+		}
+	}
+
+	path := models.ContractCustomerMedia
+	if query := contractListQuery(filter); len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var result models.Contracts
+
+	err := s.httpClient.DoXMLRequest(
+		ctx,
+		http.MethodGet, path,
+		nil, &result,
+	)
+
+	if err != nil {
+		s.logger.Error("failed to list contracts", logger.Error(err))
+		return nil, err
+	}
+
+	contracts := matchContracts(result.Contract, filter)
+	if filter.SortBy != "" {
+		sortContracts(contracts, filter.SortBy, filter.SortDesc)
+	}
+
+	if filter.Archived != models.ArchivedInclude {
+		want := 0
+		if filter.Limit > 0 {
+			want = filter.Offset + filter.Limit
+		}
+
+		contracts, err = s.filterArchived(ctx, contracts, filter.Archived, want)
+		if err != nil {
+			s.logger.Error("failed to filter archived contracts", logger.Error(err))
+			return nil, err
+		}
+	}
+
+	contracts = pageContracts(contracts, filter)
+
+	s.logger.Info("contracts listed successfully", logger.Int("total", len(result.Contract)), logger.Int("Count", len(contracts)))
+
+	return contracts, nil
+}
+
+// contractListQuery builds the server-side query parameters for a filter
+func contractListQuery(filter models.ContractFilter) url.Values {
+	query := url.Values{}
+
+	if filter.FilialID != "" {
+		query.Set("filialId", filter.FilialID)
+	}
+
+	return query
+}
+
+// matchContracts keeps the contracts matching the filter criteria.
+// Criteria are always re-applied, so servers ignoring a query parameter return the same result.
+func matchContracts(contracts []models.ContractList, filter models.ContractFilter) []models.ContractList {
+	matched := make([]models.ContractList, 0, len(contracts))
+	for _, c := range contracts {
+		if filter.Matches(c) {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// pageContracts applies the limit/offset of the filter to sorted contracts
+func pageContracts(matched []models.ContractList, filter models.ContractFilter) []models.ContractList {
+	if filter.Offset > 0 {
+		if filter.Offset >= len(matched) {
+			return []models.ContractList{}
+		}
+		matched = matched[filter.Offset:]
+	}
+
+	if filter.Limit > 0 && filter.Limit < len(matched) {
+		matched = matched[:filter.Limit]
+	}
+
+	return matched
+}
+
+// sortContracts orders contracts in place by the given field
+func sortContracts(contracts []models.ContractList, field models.ContractSortField, desc bool) {
+	less := func(a, b models.ContractList) bool {
+		switch field {
+		case models.ContractSortByName:
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		case models.ContractSortByValidFrom:
+			return compareDates(a.ValidFrom, b.ValidFrom) < 0
+		case models.ContractSortByValidUntil:
+			return compareDates(a.ValidUntil, b.ValidUntil) < 0
+		default:
+			return a.ID < b.ID
+		}
+	}
+
+	sort.SliceStable(contracts, func(i, j int) bool {
+		if desc {
+			return less(contracts[j], contracts[i])
+		}
+		return less(contracts[i], contracts[j])
+	})
+}
+
+// compareDates compares two ZR dates, unparsable values sort first
+func compareDates(a, b string) int {
+	ta, errA := models.ParseDate(a)
+	tb, errB := models.ParseDate(b)
+
+	switch {
+	case errA != nil && errB != nil:
+		return 0
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+
+	return ta.Compare(tb)
+}
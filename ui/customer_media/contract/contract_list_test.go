@@ -0,0 +1,92 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// contractListHandler serves count contracts, named C0..C<count-1> with IDs 1..count.
+// With paged set, the offset and limit query parameters are honoured.
+func contractListHandler(count int, paged bool, requests *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if requests != nil {
+			*requests = append(*requests, r.URL.RequestURI())
+		}
+
+		offset, limit := 0, count
+		if paged {
+			offset, _ = strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ = strconv.Atoi(r.URL.Query().Get("limit"))
+		}
+
+		io.WriteString(w, `<contracts xmlns="http://gsph.sub.com/cust/types">`)
+		for i := offset; i < count && i < offset+limit; i++ {
+			fmt.Fprintf(w, `<contract><id>%d</id><name>C%d</name><xValidFrom>2024-01-01</xValidFrom>`+
+				`<xValidUntil>2030-12-31+01:00</xValidUntil><filialId>%d</filialId></contract>`, i+1, i, 7+i%2)
+		}
+		io.WriteString(w, `</contracts>`)
+	}
+}
+
+func TestListContractsFiltersSortsAndPages(t *testing.T) {
+	var requests []string
+	svc := newTestService(t, contractListHandler(30, false, &requests))
+
+	got, err := svc.ListContracts(context.Background(), models.ContractFilter{
+		NamePrefix: "c1",
+		FilialID:   "7",
+		ActiveOn:   time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		SortBy:     models.ContractSortByID,
+		SortDesc:   true,
+		Offset:     1,
+		Limit:      2,
+	})
+	if err != nil {
+		t.Fatalf("ListContracts: %v", err)
+	}
+
+	// C1x with filial 7 are the even indexes: C10, C12, ... C18 (IDs 11..19), descending
+	var ids []int
+	for _, c := range got {
+		ids = append(ids, c.ID)
+	}
+	if fmt.Sprint(ids) != "[17 15]" {
+		t.Fatalf("got IDs %v, want [17 15]", ids)
+	}
+
+	if len(requests) != 1 || requests[0] != models.ContractCustomerMedia+"?filialId=7" {
+		t.Fatalf("unexpected requests %v", requests)
+	}
+}
+
+func TestContractFilterMatches(t *testing.T) {
+	c := models.ContractList{ID: 5, Name: "Acme Parking", ValidFrom: "2024-01-01", ValidUntil: "2024-12-31+01:00", FilialID: "3"}
+
+	tests := []struct {
+		name   string
+		filter models.ContractFilter
+		want   bool
+	}{
+		{"empty", models.ContractFilter{}, true},
+		{"prefix", models.ContractFilter{NamePrefix: "acme"}, true},
+		{"contains", models.ContractFilter{NameContains: "PARK"}, true},
+		{"other filial", models.ContractFilter{FilialID: "4"}, false},
+		{"id range", models.ContractFilter{MinID: 6}, false},
+		{"active", models.ContractFilter{ActiveOn: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}, true},
+		{"expired", models.ContractFilter{ActiveOn: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}, false},
+		{"expiring", models.ContractFilter{ExpiringBefore: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter.Matches(c); got != tt.want {
+			t.Errorf("%s: Matches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
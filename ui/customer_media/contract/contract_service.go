@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
 
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
 	internalhttp "github.com/yassine-manai/go_zr_sdk/internal/http"
 	"github.com/yassine-manai/go_zr_sdk/internal/logger"
 	"github.com/yassine-manai/go_zr_sdk/models"
@@ -14,6 +16,18 @@ import (
 type ContractService struct {
 	httpClient *internalhttp.Client
 	logger     logger.Logger
+
+	mu       sync.RWMutex
+	refField models.ContractRefField // Field holding external references / idempotency keys
+	refCache map[string]int          // External reference -> contract ID, verified before use
+	keyLocks keyLocks                // Serializes idempotent creations and upserts per key
+
+	refLookup    ReferenceLookup // Candidate contracts of a reference, e.g. from the ZR database
+	refScanLimit int             // Contract details fetched by a reference scan, 0 = default, < 0 = no limit
+
+	archiveLookup ArchiveLookup // Contracts matching an archive filter, e.g. from the ZR database
+
+	protectHardDelete bool // Refuse DeleteContract unless forced
 }
 
 // NewContractService creates a new contract service
@@ -21,15 +35,80 @@ func NewContractService(httpClient *internalhttp.Client, log logger.Logger) *Con
 	return &ContractService{
 		httpClient: httpClient,
 		logger:     log,
+		refField:   models.ContractRefMemo,
 	}
 }
 
-// CreateContract creates a new contract
+// SetReferenceField selects the contract field used to store external references and idempotency keys
+func (s *ContractService) SetReferenceField(field models.ContractRefField) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refField = field
+	s.refCache = nil
+}
+
+// SetReferenceLookup sets the source of candidate contracts for a reference, used instead of
+// scanning the contract list. The client sets it to the ZR database when one is configured.
+func (s *ContractService) SetReferenceLookup(lookup ReferenceLookup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refLookup = lookup
+}
+
+// SetArchiveLookup sets the source of the contract lists filtered on the soft delete flag, used
+// instead of fetching the detail of every contract. The client sets it to the ZR database when one is configured.
+func (s *ContractService) SetArchiveLookup(lookup ArchiveLookup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.archiveLookup = lookup
+}
+
+// SetReferenceScanLimit bounds the contract details fetched when a reference is looked up
+// without a ReferenceLookup. 0 restores the default (1000), a negative limit removes the bound.
+func (s *ContractService) SetReferenceScanLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refScanLimit = limit
+}
+
+// SetHardDeleteProtection makes DeleteContract refuse hard deletes unless ForceDeleteContract is used
+func (s *ContractService) SetHardDeleteProtection(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.protectHardDelete = enabled
+}
+
+func (s *ContractService) hardDeleteProtected() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.protectHardDelete
+}
+
+// referenceField returns the configured reference field
+func (s *ContractService) referenceField() models.ContractRefField {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.refField
+}
+
+// CreateContract creates a new contract.
+// When req.IdempotencyKey is set, an existing contract carrying the key is returned instead of creating a duplicate.
 func (s *ContractService) CreateContract(ctx context.Context, req models.ContractRequest) (*models.ContractDetail, error) {
+	if req.IdempotencyKey != "" {
+		if err := checkReferenceConflict(s.referenceField(), req); err != nil {
+			return nil, err
+		}
+		return s.createIdempotent(ctx, req)
+	}
+
+	return s.createContract(ctx, req.ToXML())
+}
+
+// createContract posts a contract detail
+func (s *ContractService) createContract(ctx context.Context, contractDetail models.ContractDetail) (*models.ContractDetail, error) {
+	req := contractDetail.Contract
 	s.logger.Info("creating contract", logger.String("name", req.Name), logger.String("valid_from", req.ValidFrom), logger.String("valid_until", req.ValidUntil))
 
-	// Convert to XML structure
-	contractDetail := req.ToXML()
 	var result models.ContractDetail
 
 	// Execute request
@@ -97,14 +176,17 @@ func (s *ContractService) GetContractList(ctx context.Context) (*models.Contract
 
 // UpdateContract updates an existing contract
 func (s *ContractService) UpdateContract(ctx context.Context, req models.ContractRequest) (*models.ContractDetail, error) {
-	s.logger.Info("updating contract", logger.Int("contract_id", *req.ID), logger.String("name", req.Name))
+	return s.updateContractDetail(ctx, *req.ID, req.ToXML())
+}
+
+// updateContractDetail replaces the detail of an existing contract
+func (s *ContractService) updateContractDetail(ctx context.Context, contractID int, contractDetail models.ContractDetail) (*models.ContractDetail, error) {
+	s.logger.Info("updating contract", logger.Int("contract_id", contractID), logger.String("name", contractDetail.Contract.Name))
 
-	// Convert to XML structure
-	contractDetail := req.ToXML()
 	var result models.ContractDetail
 
 	// Build path with contract ID
-	path := fmt.Sprintf(models.ContractCustomerMediaDetail, req.ID)
+	path := fmt.Sprintf(models.ContractCustomerMediaDetail, contractID)
 
 	// Execute request
 	err := s.httpClient.DoXMLRequest(
@@ -116,17 +198,28 @@ func (s *ContractService) UpdateContract(ctx context.Context, req models.Contrac
 	)
 
 	if err != nil {
-		s.logger.Error("failed to update contract", logger.Int("contract_id", *req.ID), logger.Error(err))
+		s.logger.Error("failed to update contract", logger.Int("contract_id", contractID), logger.Error(err))
 		return nil, err
 	}
 
-	s.logger.Info("contract updated successfully", logger.Int("contract_id", *req.ID), logger.String("name", result.Contract.Name))
+	s.logger.Info("contract updated successfully", logger.Int("contract_id", contractID), logger.String("name", result.Contract.Name))
 
 	return &result, nil
 }
 
-// DeleteContract deletes a contract by ID
+// DeleteContract deletes a contract by ID.
+// When hard delete protection is enabled it is refused; use ArchiveContract or ForceDeleteContract.
 func (s *ContractService) DeleteContract(ctx context.Context, contractID int) error {
+	if s.hardDeleteProtected() {
+		s.logger.Warn("hard delete refused, protection enabled", logger.Int("contract_id", contractID))
+		return errors.NewValidationError("contract_id", "hard delete is disabled, use ArchiveContract or ForceDeleteContract", contractID)
+	}
+
+	return s.ForceDeleteContract(ctx, contractID)
+}
+
+// ForceDeleteContract hard deletes a contract by ID, regardless of hard delete protection
+func (s *ContractService) ForceDeleteContract(ctx context.Context, contractID int) error {
 	s.logger.Info("deleting contract", logger.Int("contract_id", contractID))
 
 	path := fmt.Sprintf(models.ContractCustomerMediaByID, contractID)
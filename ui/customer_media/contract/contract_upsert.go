@@ -0,0 +1,160 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// UpsertContract creates or updates the contract identified by an external reference.
+// The reference is stored in the configured reference field (see SetReferenceField).
+// Only fields set in req are compared and updated; an identical contract is left untouched.
+func (s *ContractService) UpsertContract(ctx context.Context, externalRef string, req models.ContractRequest) (*models.ContractDetail, models.UpsertAction, error) {
+	if externalRef == "" {
+		return nil, "", errors.NewValidationError("externalRef", "external reference is required", nil)
+	}
+
+	field := s.referenceField()
+	if err := checkReferenceConflict(field, req); err != nil {
+		return nil, "", err
+	}
+
+	s.logger.Info("upserting contract", logger.String("external_ref", externalRef), logger.String("name", req.Name))
+
+	unlock := s.keyLocks.lock(externalRef)
+	defer unlock()
+
+	existing, err := s.lookupReference(ctx, field, externalRef, req.Name)
+	if err != nil {
+		s.logger.Error("failed to look up contract by reference", logger.String("external_ref", externalRef), logger.Error(err))
+		return nil, "", err
+	}
+
+	if existing == nil {
+		contractDetail := req.ToXML()
+		contractDetail.Contract.ID = nil
+		field.Set(&contractDetail, externalRef)
+
+		result, err := s.createContract(ctx, contractDetail)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if result.Contract.ID != nil {
+			s.cacheReference(field, externalRef, *result.Contract.ID)
+		}
+
+		s.logger.Info("contract upserted", logger.String("external_ref", externalRef), logger.String("action", string(models.UpsertCreated)))
+		return result, models.UpsertCreated, nil
+	}
+
+	contractID := *existing.Contract.ID
+
+	merged, changed := mergeContract(*existing, req, field)
+	if !changed {
+		s.logger.Info("contract upserted", logger.String("external_ref", externalRef), logger.Int("contract_id", contractID), logger.String("action", string(models.UpsertUnchanged)))
+		return existing, models.UpsertUnchanged, nil
+	}
+
+	result, err := s.updateContractDetail(ctx, contractID, merged)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.logger.Info("contract upserted", logger.String("external_ref", externalRef), logger.Int("contract_id", contractID), logger.String("action", string(models.UpsertUpdated)))
+
+	return result, models.UpsertUpdated, nil
+}
+
+// lookupReference finds a contract by reference, trying the cached contract ID first.
+// Without a ReferenceLookup, contracts named name are inspected before the others,
+// so only a renamed contract costs a scan of the whole list.
+func (s *ContractService) lookupReference(ctx context.Context, field models.ContractRefField, ref, name string) (*models.ContractDetail, error) {
+	if contractID, ok := s.cachedReference(field, ref); ok {
+		detail, err := s.GetContractById(ctx, contractID)
+		switch {
+		case err == nil && field.Get(detail) == ref:
+			if detail.Contract.ID == nil {
+				detail.Contract.ID = &contractID
+			}
+			return detail, nil
+		case err != nil && !errors.IsNotFoundError(err):
+			return nil, err
+		}
+	}
+
+	names := []string{""}
+	if name != "" {
+		names = []string{name, ""}
+	}
+
+	detail, err := s.findByReference(ctx, field, ref, names...)
+	if err != nil || detail == nil {
+		return nil, err
+	}
+
+	s.cacheReference(field, ref, *detail.Contract.ID)
+
+	return detail, nil
+}
+
+func (s *ContractService) cachedReference(field models.ContractRefField, ref string) (int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if field != s.refField {
+		return 0, false
+	}
+	contractID, ok := s.refCache[ref]
+	return contractID, ok
+}
+
+func (s *ContractService) cacheReference(field models.ContractRefField, ref string, contractID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if field != s.refField {
+		return
+	}
+	if s.refCache == nil {
+		s.refCache = make(map[string]int)
+	}
+	s.refCache[ref] = contractID
+}
+
+// mergeContract applies the fields set in req to an existing contract and reports whether anything changed.
+// The reference field itself is never overwritten.
+func mergeContract(existing models.ContractDetail, req models.ContractRequest, field models.ContractRefField) (models.ContractDetail, bool) {
+	merged := existing
+	changed := false
+
+	if req.Name != "" && req.Name != existing.Contract.Name {
+		merged.Contract.Name = req.Name
+		changed = true
+	}
+
+	if req.ValidFrom != "" && !models.SameDate(req.ValidFrom, existing.Contract.ValidFrom) {
+		merged.Contract.ValidFrom = req.ValidFrom
+		changed = true
+	}
+
+	if req.ValidUntil != "" && !models.SameDate(req.ValidUntil, existing.Contract.ValidUntil) {
+		merged.Contract.ValidUntil = req.ValidUntil
+		changed = true
+	}
+
+	if req.StdAddr != nil && (existing.Contract.StdAddr == nil || *req.StdAddr != *existing.Contract.StdAddr) {
+		addr := *req.StdAddr
+		merged.Contract.StdAddr = &addr
+		changed = true
+	}
+
+	if field != models.ContractRefMemo && req.Memo != "" && req.Memo != existing.Memo {
+		merged.Memo = req.Memo
+		changed = true
+	}
+
+	return merged, changed
+}
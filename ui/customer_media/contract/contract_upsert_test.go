@@ -0,0 +1,71 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+func TestUpsertContract(t *testing.T) {
+	store, svc := newStoreService(t)
+	ctx := context.Background()
+	req := models.ContractRequest{Name: "Acme", ValidFrom: "2024-01-01", ValidUntil: "2025-01-01"}
+
+	for i, want := range []models.UpsertAction{models.UpsertCreated, models.UpsertUnchanged} {
+		_, action, err := svc.UpsertContract(ctx, "ref-9", req)
+		if err != nil || action != want {
+			t.Fatalf("call %d: action %q, err %v", i, action, err)
+		}
+	}
+
+	req.Name = "Acme 2"
+	d, action, err := svc.UpsertContract(ctx, "ref-9", req)
+	if err != nil || action != models.UpsertUpdated {
+		t.Fatalf("rename: action %q, err %v", action, err)
+	}
+	if d.Contract.Name != "Acme 2" || d.Memo != "ref-9" || len(store.Items) != 1 {
+		t.Fatalf("name %q, memo %q, %d contracts", d.Contract.Name, d.Memo, len(store.Items))
+	}
+}
+
+func TestUpsertContractNarrowsByName(t *testing.T) {
+	store, svc := newStoreService(t)
+	for i := 0; i < 10; i++ {
+		store.Add(models.ContractDetail{Contract: models.Contract{Name: "Other"}})
+	}
+	store.Add(models.ContractDetail{Contract: models.Contract{Name: "Acme"}, Memo: "ref-9"})
+
+	_, action, err := svc.UpsertContract(context.Background(), "ref-9", models.ContractRequest{Name: "Acme"})
+	if err != nil || action != models.UpsertUnchanged {
+		t.Fatalf("action %q, err %v", action, err)
+	}
+	if store.Details != 1 {
+		t.Fatalf("%d detail requests on a cache miss, want 1", store.Details)
+	}
+}
+
+func TestUpsertContractFindsRenamedContract(t *testing.T) {
+	store, svc := newStoreService(t)
+	store.Add(models.ContractDetail{Contract: models.Contract{Name: "Acme"}})
+	id := store.Add(models.ContractDetail{Contract: models.Contract{Name: "Old name"}, Memo: "ref-9"})
+
+	d, action, err := svc.UpsertContract(context.Background(), "ref-9", models.ContractRequest{Name: "Acme"})
+	if err != nil || action != models.UpsertUpdated || *d.Contract.ID != id {
+		t.Fatalf("action %q, err %v", action, err)
+	}
+	// Acme is inspected by the name scan, not again by the full scan
+	if store.Details != 2 {
+		t.Fatalf("%d detail requests, want 2", store.Details)
+	}
+}
+
+func TestUpsertContractRejectsMemoOnMemoReference(t *testing.T) {
+	store, svc := newStoreService(t)
+
+	_, _, err := svc.UpsertContract(context.Background(), "ref-9", models.ContractRequest{Name: "Acme", Memo: "note"})
+	if !errors.IsValidationError(err) || len(store.Items) != 0 {
+		t.Fatalf("got %v, %d contracts", err, len(store.Items))
+	}
+}
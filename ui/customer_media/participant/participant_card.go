@@ -0,0 +1,312 @@
+package participant
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	internalhttp "github.com/yassine-manai/go_zr_sdk/internal/http"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// Card audit events
+const (
+	auditCardAssign  = "card.assign"
+	auditCardBlock   = "card.block"
+	auditCardUnblock = "card.unblock"
+	auditCardReplace = "card.replace"
+)
+
+// AssignCard assigns a media/card number to a participant, as an active card.
+// A card number owned by another participant is rejected (see FindByCard for the cost of the check).
+func (s *ParticipantService) AssignCard(ctx context.Context, contractID, participantID int, cardNo string) (*models.ParticipantDetail, error) {
+	if cardNo == "" {
+		return nil, errors.NewValidationError("cardNo", "card number is required", nil)
+	}
+
+	detail, err := s.GetParticipantById(ctx, contractID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCardFree(ctx, participantID, "cardNo", cardNo); err != nil {
+		return nil, err
+	}
+
+	previous := ""
+	if detail.Identification != nil {
+		previous = detail.Identification.CardNo
+	}
+	setCard(detail, cardNo)
+
+	result, err := s.updateParticipantDetail(ctx, contractID, participantID, *detail)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(auditCardAssign, contractID, participantID, logger.String("card_no", cardNo), logger.String("previous_card_no", previous))
+
+	return result, nil
+}
+
+// BlockCard blocks the card of a participant
+func (s *ParticipantService) BlockCard(ctx context.Context, contractID, participantID int, reason string) (*models.ParticipantDetail, error) {
+	return s.setCardStatus(ctx, contractID, participantID, models.CardStatusBlocked, reason)
+}
+
+// UnblockCard unblocks the card of a participant
+func (s *ParticipantService) UnblockCard(ctx context.Context, contractID, participantID int, reason string) (*models.ParticipantDetail, error) {
+	return s.setCardStatus(ctx, contractID, participantID, models.CardStatusActive, reason)
+}
+
+// setCardStatus validates and applies a card status transition
+func (s *ParticipantService) setCardStatus(ctx context.Context, contractID, participantID, status int, reason string) (*models.ParticipantDetail, error) {
+	detail, err := s.GetParticipantById(ctx, contractID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if detail.Identification == nil || detail.Identification.CardNo == "" {
+		return nil, errors.NewValidationError("cardNo", "participant has no card assigned", participantID)
+	}
+	if detail.Identification.Status == status {
+		if status == models.CardStatusBlocked {
+			return nil, errors.NewValidationError("status", "card is already blocked", detail.Identification.CardNo)
+		}
+		return nil, errors.NewValidationError("status", "card is not blocked", detail.Identification.CardNo)
+	}
+
+	detail.Identification.Status = status
+
+	result, err := s.updateParticipantDetail(ctx, contractID, participantID, *detail)
+	if err != nil {
+		return nil, err
+	}
+
+	event := auditCardUnblock
+	if status == models.CardStatusBlocked {
+		event = auditCardBlock
+	}
+	s.audit(event, contractID, participantID, logger.String("card_no", detail.Identification.CardNo), logger.String("reason", reason))
+
+	return result, nil
+}
+
+// ReplaceLostCard blocks the current card of a participant and issues a new card number.
+// The lost card is kept, blocked, in the participant's BlockedCards. A new card number owned by
+// another participant is rejected, as by AssignCard.
+// If issuing the new card fails, the participant is restored to its previous state.
+func (s *ParticipantService) ReplaceLostCard(ctx context.Context, contractID, participantID int, newCardNo, reason string) (*models.ParticipantDetail, error) {
+	if newCardNo == "" {
+		return nil, errors.NewValidationError("newCardNo", "new card number is required", nil)
+	}
+
+	original, err := s.GetParticipantById(ctx, contractID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if original.Identification == nil || original.Identification.CardNo == "" {
+		return nil, errors.NewValidationError("cardNo", "participant has no card to replace", participantID)
+	}
+	if original.Identification.CardNo == newCardNo {
+		return nil, errors.NewValidationError("newCardNo", "new card number must differ from the lost card", newCardNo)
+	}
+	for _, card := range original.BlockedCards {
+		if card.CardNo == newCardNo {
+			return nil, errors.NewValidationError("newCardNo", "new card number was blocked as lost", newCardNo)
+		}
+	}
+	if err := s.checkCardFree(ctx, participantID, "newCardNo", newCardNo); err != nil {
+		return nil, err
+	}
+
+	oldCardNo := original.Identification.CardNo
+
+	// Step 1: block the lost card
+	blocked := cloneDetail(original)
+	blocked.Identification.Status = models.CardStatusBlocked
+	if _, err := s.updateParticipantDetail(ctx, contractID, participantID, blocked); err != nil {
+		return nil, err
+	}
+
+	// Step 2: issue the new card, keeping the lost one blocked
+	issued := cloneDetail(original)
+	lost := *original.Identification
+	lost.Status = models.CardStatusBlocked
+	issued.BlockedCards = append(issued.BlockedCards, lost)
+	issued.Identification.CardNo = newCardNo
+	issued.Identification.Status = models.CardStatusActive
+
+	result, err := s.updateParticipantDetail(ctx, contractID, participantID, issued)
+	if err != nil {
+		s.logger.Warn("issuing replacement card failed, rolling back", logger.Int("contract_id", contractID), logger.Int("participant_id", participantID), logger.Error(err))
+
+		// The rollback must run even when the failure is ctx being cancelled
+		rbCtx := context.WithoutCancel(ctx)
+		if _, rbErr := s.updateParticipantDetail(rbCtx, contractID, participantID, cloneDetail(original)); rbErr != nil {
+			s.logger.Error("card replacement rollback failed", logger.Int("contract_id", contractID), logger.Int("participant_id", participantID), logger.Error(rbErr))
+			return nil, errors.NewSDKError(
+				errors.ErrorTypeInternal,
+				fmt.Sprintf("card replacement failed and rollback failed, card %s is left blocked: %v", oldCardNo, rbErr),
+				err,
+			)
+		}
+		return nil, err
+	}
+
+	s.audit(auditCardReplace, contractID, participantID, logger.String("old_card_no", oldCardNo), logger.String("card_no", newCardNo), logger.String("reason", reason))
+
+	return result, nil
+}
+
+// FindByCard finds the participant and contract owning a card number as its current card.
+// With an OwnerLookup (see SetOwnerLookup) this is a single query; otherwise every participant
+// of every contract may be inspected, so this is an expensive call.
+func (s *ParticipantService) FindByCard(ctx context.Context, cardNo string) (*models.ParticipantOwner, error) {
+	s.logger.Info("looking up card owner", logger.String("card_no", cardNo))
+
+	owner, err := s.cardOwner(ctx, cardNo)
+	if err != nil {
+		s.logger.Error("failed to look up card owner", logger.String("card_no", cardNo), logger.Error(err))
+		return nil, err
+	}
+
+	if owner == nil {
+		return nil, errors.NewNotFoundError("no participant owns this card", "card", cardNo)
+	}
+
+	s.logger.Info("card owner found", logger.String("card_no", cardNo), logger.Int("contract_id", owner.Contract.ID), logger.Int("participant_id", *owner.Participant.Participant.ID))
+
+	return owner, nil
+}
+
+// cardOwner returns the participant owning a card number as its current card, or nil
+func (s *ParticipantService) cardOwner(ctx context.Context, cardNo string) (*models.ParticipantOwner, error) {
+	return s.findOwner(ctx, models.ParticipantFilter{CardNumber: cardNo}, func(detail *models.ParticipantDetail) bool {
+		return detail.Identification != nil && detail.Identification.CardNo == cardNo
+	})
+}
+
+// checkCardFree rejects a card number owned by another participant than participantID
+func (s *ParticipantService) checkCardFree(ctx context.Context, participantID int, field, cardNo string) error {
+	owner, err := s.cardOwner(ctx, cardNo)
+	if err != nil {
+		return err
+	}
+
+	if owner != nil {
+		if id := owner.Participant.Participant.ID; id == nil || *id != participantID {
+			return errors.NewValidationError(field, "card number is assigned to another participant", cardNo)
+		}
+	}
+
+	return nil
+}
+
+// OwnerLookup returns the first participant matching filter with its contract, or a not found error
+type OwnerLookup func(ctx context.Context, filter models.ParticipantFilter) (*models.ParticipantOwner, error)
+
+// findOwner returns the first participant matching: through the owner lookup with filter when
+// one is set, otherwise by walking every participant of every contract
+func (s *ParticipantService) findOwner(ctx context.Context, filter models.ParticipantFilter, match func(*models.ParticipantDetail) bool) (*models.ParticipantOwner, error) {
+	if lookup := s.ownerLookup(); lookup != nil {
+		owner, err := lookup(ctx, filter)
+		if errors.IsNotFoundError(err) {
+			return nil, nil
+		}
+		return owner, err
+	}
+
+	var owner *models.ParticipantOwner
+
+	err := s.eachParticipant(ctx, func(c models.ContractList, detail *models.ParticipantDetail) bool {
+		if match(detail) {
+			owner = &models.ParticipantOwner{Contract: c, Participant: *detail}
+			return false
+		}
+		return true
+	})
+
+	return owner, err
+}
+
+// eachParticipant calls fn with the detail of every participant of every contract, until fn returns false
+func (s *ParticipantService) eachParticipant(ctx context.Context, fn func(models.ContractList, *models.ParticipantDetail) bool) error {
+	var contracts []models.ContractList
+
+	err := s.httpClient.DoXMLStream(ctx, http.MethodGet, models.ContractCustomerMedia, nil, func(dec *xml.Decoder) error {
+		_, err := internalhttp.DecodeEach(dec, "contract", func(c models.ContractList) bool {
+			contracts = append(contracts, c)
+			return true
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range contracts {
+		participants, err := s.GetParticipantList(ctx, c.ID)
+		if err != nil {
+			if errors.IsNotFoundError(err) {
+				continue
+			}
+			return err
+		}
+
+		for _, p := range participants.Participant {
+			detail, err := s.GetParticipantById(ctx, c.ID, p.ID)
+			if err != nil {
+				if errors.IsNotFoundError(err) {
+					continue
+				}
+				return err
+			}
+
+			if !fn(c, detail) {
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+// setCard sets the card number of a participant as an active card, creating its identification if needed
+func setCard(detail *models.ParticipantDetail, cardNo string) {
+	if detail.Identification == nil {
+		detail.Identification = &models.Identification{
+			ValidFrom:  detail.Participant.ValidFrom,
+			ValidUntil: detail.Participant.ValidUntil,
+		}
+	}
+	detail.Identification.CardNo = cardNo
+	detail.Identification.Status = models.CardStatusActive
+}
+
+// cloneDetail copies a participant detail, including its identification and blocked cards
+func cloneDetail(detail *models.ParticipantDetail) models.ParticipantDetail {
+	clone := *detail
+	if detail.Identification != nil {
+		identification := *detail.Identification
+		clone.Identification = &identification
+	}
+	clone.BlockedCards = slices.Clone(detail.BlockedCards)
+	return clone
+}
+
+// audit logs a structured participant event
+func (s *ParticipantService) audit(event string, contractID, participantID int, fields ...logger.Field) {
+	all := append([]logger.Field{
+		logger.String("event", event),
+		logger.Int("contract_id", contractID),
+		logger.Int("participant_id", participantID),
+	}, fields...)
+
+	s.logger.Info("participant audit event", all...)
+}
@@ -0,0 +1,217 @@
+package participant
+
+import (
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/testutil"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+func newCardParticipant(store *testutil.ParticipantStore, cardNo string) int {
+	return store.Add(models.ParticipantDetail{
+		Participant:    models.Participant{ContractID: 7, Name: "Bob", ValidFrom: "2026-01-01", ValidUntil: "2027-01-01"},
+		Identification: &models.Identification{CardNo: cardNo, CardClass: 3},
+	})
+}
+
+func TestBlockAndUnblockCard(t *testing.T) {
+	store, svc := newStoreService(t)
+	ctx := context.Background()
+	id := newCardParticipant(store, "111")
+
+	if _, err := svc.BlockCard(ctx, 7, id, "stolen"); err != nil {
+		t.Fatal(err)
+	}
+	if !store.Get(id).Identification.IsBlocked() {
+		t.Fatal("card not blocked")
+	}
+	if _, err := svc.BlockCard(ctx, 7, id, "stolen"); !errors.IsValidationError(err) {
+		t.Fatalf("blocking twice: got %v", err)
+	}
+
+	if _, err := svc.UnblockCard(ctx, 7, id, "found"); err != nil {
+		t.Fatal(err)
+	}
+	if store.Get(id).Identification.IsBlocked() {
+		t.Fatal("card still blocked")
+	}
+
+	bare := store.Add(models.ParticipantDetail{Participant: models.Participant{ContractID: 7, Name: "Al"}})
+	if _, err := svc.BlockCard(ctx, 7, bare, "stolen"); !errors.IsValidationError(err) {
+		t.Fatalf("blocking without card: got %v", err)
+	}
+}
+
+func TestAssignCard(t *testing.T) {
+	store, svc := newStoreService(t)
+	id := store.Add(models.ParticipantDetail{Participant: models.Participant{ContractID: 7, Name: "Al", ValidFrom: "2026-01-01"}})
+
+	if _, err := svc.AssignCard(context.Background(), 7, id, "222"); err != nil {
+		t.Fatal(err)
+	}
+	card := store.Get(id).Identification
+	if card == nil || card.CardNo != "222" || card.ValidFrom != "2026-01-01" {
+		t.Fatalf("got card %+v", card)
+	}
+
+	// Reassigning its own card is allowed
+	if _, err := svc.AssignCard(context.Background(), 7, id, "222"); err != nil {
+		t.Fatalf("reassigning the same card: %v", err)
+	}
+}
+
+func TestAssignCardActivatesCard(t *testing.T) {
+	store, svc := newStoreService(t)
+	id := store.Add(models.ParticipantDetail{
+		Participant:    models.Participant{ContractID: 7, Name: "Al"},
+		Identification: &models.Identification{CardNo: "111", Status: models.CardStatusBlocked},
+	})
+
+	if _, err := svc.AssignCard(context.Background(), 7, id, "222"); err != nil {
+		t.Fatal(err)
+	}
+	if card := store.Get(id).Identification; card.CardNo != "222" || card.IsBlocked() {
+		t.Fatalf("new card issued blocked: %+v", card)
+	}
+}
+
+func TestAssignCardRejectsOwnedCard(t *testing.T) {
+	store, svc := newStoreService(t)
+	newCardParticipant(store, "111")
+	id := store.Add(models.ParticipantDetail{Participant: models.Participant{ContractID: 8, Name: "Al"}})
+
+	if _, err := svc.AssignCard(context.Background(), 8, id, "111"); !errors.IsValidationError(err) {
+		t.Fatalf("assigning another participant's card: got %v", err)
+	}
+	if len(store.Puts) != 0 {
+		t.Fatalf("%d PUTs for a rejected card", len(store.Puts))
+	}
+}
+
+func TestReplaceLostCard(t *testing.T) {
+	store, svc := newStoreService(t)
+	id := newCardParticipant(store, "111")
+
+	result, err := svc.ReplaceLostCard(context.Background(), 7, id, "222", "lost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := store.Get(id)
+	if d.Identification.CardNo != "222" || d.Identification.IsBlocked() || d.Identification.CardClass != 3 {
+		t.Fatalf("new card %+v", d.Identification)
+	}
+	if len(d.BlockedCards) != 1 || d.BlockedCards[0].CardNo != "111" || !d.BlockedCards[0].IsBlocked() {
+		t.Fatalf("lost card not kept blocked: %+v", d.BlockedCards)
+	}
+	if len(result.BlockedCards) != 1 {
+		t.Fatalf("result misses the lost card: %+v", result.BlockedCards)
+	}
+	if len(store.Puts) != 2 || !store.Puts[0].Identification.IsBlocked() || store.Puts[0].Identification.CardNo != "111" {
+		t.Fatalf("lost card was not blocked first: %+v", store.Puts)
+	}
+
+	// A card blocked as lost cannot be issued again
+	if _, err := svc.ReplaceLostCard(context.Background(), 7, id, "111", "lost"); !errors.IsValidationError(err) {
+		t.Fatalf("reissuing a lost card: got %v", err)
+	}
+
+	// Nor can a card owned by another participant
+	newCardParticipant(store, "333")
+	puts := len(store.Puts)
+	if _, err := svc.ReplaceLostCard(context.Background(), 7, id, "333", "lost"); !errors.IsValidationError(err) {
+		t.Fatalf("issuing another participant's card: got %v", err)
+	}
+	if len(store.Puts) != puts {
+		t.Fatal("rejected replacement blocked the current card")
+	}
+}
+
+func TestReplaceLostCardRollsBack(t *testing.T) {
+	store, svc := newStoreService(t)
+	id := newCardParticipant(store, "111")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Issuing the new card fails and the caller gives up: the rollback must still run
+	store.FailPut = func(n int) bool {
+		if n == 2 {
+			cancel()
+			return true
+		}
+		return false
+	}
+
+	if _, err := svc.ReplaceLostCard(ctx, 7, id, "222", "lost"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if len(store.Puts) != 3 {
+		t.Fatalf("%d PUTs, want block, issue and rollback", len(store.Puts))
+	}
+	d := store.Get(id)
+	if d.Identification.CardNo != "111" || d.Identification.IsBlocked() || len(d.BlockedCards) != 0 {
+		t.Fatalf("participant not restored: %+v %+v", d.Identification, d.BlockedCards)
+	}
+}
+
+func TestBlockedCardsXML(t *testing.T) {
+	out, err := xml.Marshal(models.ParticipantDetail{Identification: &models.Identification{CardNo: "1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), "blockedCard") {
+		t.Fatalf("empty blocked cards sent: %s", out)
+	}
+}
+
+func TestFindByCard(t *testing.T) {
+	store, svc := newStoreService(t)
+	newCardParticipant(store, "111")
+	id := store.Add(models.ParticipantDetail{
+		Participant:    models.Participant{ContractID: 8, Name: "Al"},
+		Identification: &models.Identification{CardNo: "222"},
+	})
+
+	owner, err := svc.FindByCard(context.Background(), "222")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner.Contract.ID != 8 || *owner.Participant.Participant.ID != id {
+		t.Fatalf("got %+v", owner)
+	}
+
+	if _, err := svc.FindByCard(context.Background(), "333"); !errors.IsNotFoundError(err) {
+		t.Fatalf("unknown card: got %v", err)
+	}
+}
+
+func TestFindByCardUsesOwnerLookup(t *testing.T) {
+	store, svc := newStoreService(t)
+	newCardParticipant(store, "111")
+
+	var filters []models.ParticipantFilter
+	svc.SetOwnerLookup(func(ctx context.Context, filter models.ParticipantFilter) (*models.ParticipantOwner, error) {
+		filters = append(filters, filter)
+		if filter.CardNumber != "111" {
+			return nil, errors.NewNotFoundError("no participant matches", "participant", "")
+		}
+		id := 5
+		return &models.ParticipantOwner{Contract: models.ContractList{ID: 7}, Participant: models.ParticipantDetail{Participant: models.Participant{ID: &id}}}, nil
+	})
+
+	if owner, err := svc.FindByCard(context.Background(), "111"); err != nil || owner.Contract.ID != 7 {
+		t.Fatalf("got %+v, %v", owner, err)
+	}
+	if _, err := svc.FindByCard(context.Background(), "333"); !errors.IsNotFoundError(err) {
+		t.Fatalf("unknown card: got %v", err)
+	}
+	if store.Gets != 0 || len(filters) != 2 {
+		t.Fatalf("%d participant GETs, %d lookups", store.Gets, len(filters))
+	}
+}
@@ -0,0 +1,281 @@
+package participant
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	stderrors "errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/bulk"
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// utf8BOM is the byte order mark starting the CSV files exported by Excel
+const utf8BOM = "\ufeff"
+
+// requiredImportFields must be mapped to a CSV column
+var requiredImportFields = []models.ParticipantField{
+	models.ParticipantFieldName,
+	models.ParticipantFieldValidFrom,
+	models.ParticipantFieldValidUntil,
+}
+
+// optionalImportFields are read when their column is present
+var optionalImportFields = []models.ParticipantField{
+	models.ParticipantFieldFirstName,
+	models.ParticipantFieldCardNumber,
+	models.ParticipantFieldLicensePlate,
+}
+
+// ImportCSV reads participants of a contract from CSV and creates them.
+// Every row is validated first, a malformed CSV row counting as invalid: unless SkipInvalid is set,
+// a single invalid row aborts the import before anything is created. With DryRun nothing is created at all.
+// The returned report carries row-level results and can be written back as CSV.
+func (s *ParticipantService) ImportCSV(ctx context.Context, contractID int, r io.Reader, opts models.ParticipantImportOptions) (*models.ParticipantImportReport, error) {
+	s.logger.Info("importing participants from CSV", logger.Int("contract_id", contractID), logger.Bool("dry_run", opts.DryRun))
+
+	report, err := parseImport(contractID, r, opts)
+	if err != nil {
+		s.logger.Error("failed to read participant CSV", logger.Int("contract_id", contractID), logger.Error(err))
+		return nil, err
+	}
+
+	if opts.DryRun || (report.Invalid > 0 && !opts.SkipInvalid) {
+		if !opts.DryRun {
+			markSkipped(report)
+		}
+
+		s.logger.Info("participant CSV validated", logger.Int("contract_id", contractID), logger.Int("valid", report.Valid), logger.Int("invalid", report.Invalid))
+
+		if report.Invalid > 0 && !opts.DryRun {
+			return report, errors.NewSDKError(errors.ErrorTypeValidation, "participant CSV contains invalid rows, nothing was created", nil)
+		}
+		return report, nil
+	}
+
+	// Create the valid rows with bounded concurrency
+	var pending []int
+	for i, row := range report.Rows {
+		if row.Status == models.ImportStatusValid {
+			pending = append(pending, i)
+		}
+	}
+
+	results := bulk.Run(ctx, pending, opts.Bulk, func(ctx context.Context, index int) (int, error) {
+		created, err := s.CreateParticipant(ctx, report.Rows[index].Request)
+		if err != nil {
+			return 0, err
+		}
+		if created.Participant.ID == nil {
+			return 0, nil
+		}
+		return *created.Participant.ID, nil
+	})
+
+	for _, res := range results.Results {
+		row := &report.Rows[pending[res.Index]]
+		switch {
+		case res.Skipped:
+			row.Status = models.ImportStatusSkipped
+			report.Skipped++
+		case res.Err != nil:
+			row.Status = models.ImportStatusFailed
+			row.Errors = append(row.Errors, res.Err)
+			report.Failed++
+		default:
+			row.Status = models.ImportStatusCreated
+			row.ID = res.ID
+			report.Created++
+		}
+	}
+
+	s.logger.Info("participant CSV import finished",
+		logger.Int("contract_id", contractID),
+		logger.Int("created", report.Created),
+		logger.Int("failed", report.Failed),
+		logger.Int("invalid", report.Invalid),
+		logger.Int("skipped", report.Skipped),
+	)
+
+	return report, ctx.Err()
+}
+
+// parseImport reads and validates every CSV row. A leading UTF-8 byte order mark, as written by
+// Excel, is skipped.
+func parseImport(contractID int, r io.Reader, opts models.ParticipantImportOptions) (*models.ParticipantImportReport, error) {
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(len(utf8BOM)); err == nil && string(bom) == utf8BOM {
+		buffered.Discard(len(utf8BOM))
+	}
+
+	reader := csv.NewReader(buffered)
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.NewValidationError("csv", "file is empty", nil)
+	}
+	if err != nil {
+		return nil, errors.NewSDKError(errors.ErrorTypeValidation, "failed to read CSV header", err)
+	}
+
+	columns, err := resolveColumns(header, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ParticipantImportReport{Header: header, Comma: opts.Comma, DryRun: opts.DryRun}
+	cards := make(map[string]int) // card number -> first line using it
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if stderrors.As(err, &parseErr) {
+			// A malformed row is reported like an invalid one, the reader resumes on the next line
+			report.Rows = append(report.Rows, models.ParticipantImportRow{
+				Line:   parseErr.StartLine,
+				Record: record,
+				Status: models.ImportStatusInvalid,
+				Errors: []error{errors.NewValidationError("csv", "malformed row: "+parseErr.Err.Error(), parseErr.Line)},
+			})
+			report.Invalid++
+			continue
+		}
+		if err != nil {
+			return nil, errors.NewSDKError(errors.ErrorTypeValidation, "failed to read CSV", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := models.ParticipantImportRow{Line: line, Record: record}
+
+		value := func(field models.ParticipantField) string {
+			index, ok := columns[field]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		row.Request = models.ParticipantRequest{
+			ContractID:   contractID,
+			Name:         value(models.ParticipantFieldName),
+			FirstName:    value(models.ParticipantFieldFirstName),
+			ValidFrom:    value(models.ParticipantFieldValidFrom),
+			ValidUntil:   value(models.ParticipantFieldValidUntil),
+			CardNumber:   value(models.ParticipantFieldCardNumber),
+			LicensePlate: value(models.ParticipantFieldLicensePlate),
+		}
+
+		row.Errors = validateImportRow(row, len(header), cards)
+
+		if len(row.Errors) > 0 {
+			row.Status = models.ImportStatusInvalid
+			report.Invalid++
+		} else {
+			row.Status = models.ImportStatusValid
+			report.Valid++
+		}
+
+		report.Rows = append(report.Rows, row)
+	}
+
+	return report, nil
+}
+
+// resolveColumns maps participant fields to CSV column indexes
+func resolveColumns(header []string, opts models.ParticipantImportOptions) (map[models.ParticipantField]int, error) {
+	indexes := make(map[string]int, len(header))
+	for i, name := range header {
+		indexes[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	columns := make(map[models.ParticipantField]int)
+	missing := &errors.MultiValidationError{}
+
+	for _, field := range requiredImportFields {
+		index, ok := indexes[strings.ToLower(opts.Column(field))]
+		if !ok {
+			missing.Add(string(field), "required column is missing", opts.Column(field))
+			continue
+		}
+		columns[field] = index
+	}
+
+	for _, field := range optionalImportFields {
+		if index, ok := indexes[strings.ToLower(opts.Column(field))]; ok {
+			columns[field] = index
+		}
+	}
+
+	if err := missing.Return(); err != nil {
+		return nil, err
+	}
+
+	return columns, nil
+}
+
+// validateImportRow returns the validation errors of a row
+func validateImportRow(row models.ParticipantImportRow, columns int, cards map[string]int) []error {
+	var errs []error
+
+	if len(row.Record) != columns {
+		errs = append(errs, errors.NewValidationError("csv", "unexpected number of columns", len(row.Record)))
+	}
+
+	if err := row.Request.Validate(); err != nil {
+		if multi, ok := err.(*errors.MultiValidationError); ok {
+			for _, e := range multi.Errors {
+				errs = append(errs, e)
+			}
+		} else {
+			errs = append(errs, err)
+		}
+	}
+
+	from, errFrom := models.ParseDate(row.Request.ValidFrom)
+	if row.Request.ValidFrom != "" && errFrom != nil {
+		errs = append(errs, errors.NewValidationError("validFrom", "invalid date", row.Request.ValidFrom))
+	}
+
+	until, errUntil := models.ParseDate(row.Request.ValidUntil)
+	if row.Request.ValidUntil != "" && errUntil != nil {
+		errs = append(errs, errors.NewValidationError("validUntil", "invalid date", row.Request.ValidUntil))
+	}
+
+	if errFrom == nil && errUntil == nil && until.Before(from) {
+		errs = append(errs, errors.NewValidationError("validUntil", "validity ends before it starts", row.Request.ValidUntil))
+	}
+
+	if card := row.Request.CardNumber; card != "" {
+		if first, ok := cards[card]; ok {
+			errs = append(errs, errors.NewValidationError("cardNumber", "card number already used on line "+strconv.Itoa(first), card))
+		} else {
+			cards[card] = row.Line
+		}
+	}
+
+	return errs
+}
+
+// markSkipped flags the valid rows of an aborted import as skipped
+func markSkipped(report *models.ParticipantImportReport) {
+	for i := range report.Rows {
+		if report.Rows[i].Status == models.ImportStatusValid {
+			report.Rows[i].Status = models.ImportStatusSkipped
+			report.Skipped++
+		}
+	}
+}
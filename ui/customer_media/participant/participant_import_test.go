@@ -0,0 +1,128 @@
+package participant
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+const importCSV = `name,validFrom,validUntil,cardNumber
+Bob,2026-01-01,2027-01-01,111
+Al,2026-01-01,2025-01-01,222
+Eve,2026-01-01,2027-01-01,111
+Joe,2026-01-01,2027-01-01,333
+`
+
+func TestImportCSV(t *testing.T) {
+	store, svc := newStoreService(t)
+
+	report, err := svc.ImportCSV(context.Background(), 7, strings.NewReader("Name;From;Until\nBob;2026-01-01;2027-01-01\n"), models.ParticipantImportOptions{
+		Comma:   ';',
+		Columns: map[models.ParticipantField]string{models.ParticipantFieldName: "Name", models.ParticipantFieldValidFrom: "From", models.ParticipantFieldValidUntil: "Until"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Created != 1 || len(store.Items) != 1 {
+		t.Fatalf("created %d, stored %d", report.Created, len(store.Items))
+	}
+
+	var out bytes.Buffer
+	if err := report.WriteCSV(&out); err != nil {
+		t.Fatal(err)
+	}
+	want := "Name;From;Until;participantId;status;error\nBob;2026-01-01;2027-01-01;100;created;\n"
+	if out.String() != want {
+		t.Fatalf("report CSV:\n%s", out.String())
+	}
+}
+
+func TestImportCSVAbortsOnInvalidRows(t *testing.T) {
+	store, svc := newStoreService(t)
+
+	report, err := svc.ImportCSV(context.Background(), 7, strings.NewReader(importCSV), models.ParticipantImportOptions{})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(store.Items) != 0 {
+		t.Fatalf("%d participants created", len(store.Items))
+	}
+	if report.Invalid != 2 || report.Skipped != 2 {
+		t.Fatalf("invalid %d, skipped %d", report.Invalid, report.Skipped)
+	}
+	if row := report.Rows[2]; row.Line != 4 || !strings.Contains(row.Errors[0].Error(), "line 2") {
+		t.Fatalf("duplicate card row %+v", row)
+	}
+}
+
+func TestImportCSVSkipInvalid(t *testing.T) {
+	store, svc := newStoreService(t)
+
+	report, err := svc.ImportCSV(context.Background(), 7, strings.NewReader(importCSV), models.ParticipantImportOptions{SkipInvalid: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Created != 2 || report.Invalid != 2 || len(store.Items) != 2 {
+		t.Fatalf("created %d, invalid %d, stored %d", report.Created, report.Invalid, len(store.Items))
+	}
+}
+
+func TestImportCSVMalformedRow(t *testing.T) {
+	store, svc := newStoreService(t)
+	input := "name,validFrom,validUntil\nBob,2026-01-01,2027-01-01\nA\"l,2026-01-01,2027-01-01\nJoe,2026-01-01,2027-01-01\n"
+
+	report, err := svc.ImportCSV(context.Background(), 7, strings.NewReader(input), models.ParticipantImportOptions{SkipInvalid: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Created != 2 || report.Invalid != 1 || len(store.Items) != 2 {
+		t.Fatalf("created %d, invalid %d, stored %d", report.Created, report.Invalid, len(store.Items))
+	}
+
+	row := report.Rows[1]
+	if row.Line != 3 || row.Status != models.ImportStatusInvalid || !errors.IsValidationError(row.Errors[0]) {
+		t.Fatalf("malformed row %+v", row)
+	}
+}
+
+func TestImportCSVDryRun(t *testing.T) {
+	store, svc := newStoreService(t)
+
+	report, err := svc.ImportCSV(context.Background(), 7, strings.NewReader(importCSV), models.ParticipantImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Valid != 2 || report.Invalid != 2 || len(store.Items) != 0 {
+		t.Fatalf("valid %d, invalid %d, stored %d", report.Valid, report.Invalid, len(store.Items))
+	}
+}
+
+func TestImportCSVMissingColumn(t *testing.T) {
+	_, svc := newStoreService(t)
+
+	_, err := svc.ImportCSV(context.Background(), 7, strings.NewReader("name,validFrom\nBob,2026-01-01\n"), models.ParticipantImportOptions{})
+	if !errors.IsValidationError(err) {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestImportCSVSkipsByteOrderMark(t *testing.T) {
+	for name, csv := range map[string]string{
+		"plain header":  "\ufeffname,validFrom,validUntil\nBob,2026-01-01,2027-01-01\n",
+		"quoted header": "\ufeff\"name\",\"validFrom\",\"validUntil\"\nBob,2026-01-01,2027-01-01\n",
+	} {
+		store, svc := newStoreService(t)
+
+		report, err := svc.ImportCSV(context.Background(), 7, strings.NewReader(csv), models.ParticipantImportOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if report.Created != 1 || len(store.Items) != 1 || report.Header[0] != "name" {
+			t.Fatalf("%s: created %d, stored %d, header %q", name, report.Created, len(store.Items), report.Header)
+		}
+	}
+}
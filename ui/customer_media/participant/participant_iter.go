@@ -0,0 +1,55 @@
+package participant
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	internalhttp "github.com/yassine-manai/go_zr_sdk/internal/http"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/internal/paging"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// IterateParticipants returns a sequence over every participant of a contract, fetching pages on demand.
+// Breaking out of the loop stops fetching. On ZR versions without paging the full
+// response is streamed and decoded one participant at a time.
+// The request timeout only bounds the wait for each page, not the time spent in the loop body.
+func (s *ParticipantService) IterateParticipants(ctx context.Context, contractID int, opts models.PageOptions) iter.Seq2[models.ParticipantList, error] {
+	key := func(p models.ParticipantList) int { return p.ID }
+
+	fetch := func(ctx context.Context, offset, limit int, yield func(models.ParticipantList) bool) (int, error) {
+		return s.fetchParticipantPage(ctx, contractID, offset, limit, yield)
+	}
+
+	return paging.Iterate(ctx, opts.PageSize, key, fetch)
+}
+
+// fetchParticipantPage streams one page of the participant list of a contract
+func (s *ParticipantService) fetchParticipantPage(ctx context.Context, contractID, offset, limit int, yield func(models.ParticipantList) bool) (int, error) {
+	s.logger.Debug("fetching participant page", logger.Int("contract_id", contractID), logger.Int("offset", offset), logger.Int("limit", limit))
+
+	query := url.Values{}
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("limit", strconv.Itoa(limit))
+	path := fmt.Sprintf(models.ParticipantCustomerMedia, contractID) + "?" + query.Encode()
+
+	var count int
+
+	err := s.httpClient.DoXMLStream(ctx, http.MethodGet, path, nil, func(dec *xml.Decoder) error {
+		n, err := internalhttp.DecodeEach(dec, "consumer", yield)
+		count = n
+		return err
+	})
+
+	if err != nil {
+		s.logger.Error("failed to fetch participant page", logger.Int("contract_id", contractID), logger.Int("offset", offset), logger.Error(err))
+		return count, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,55 @@
+package participant
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/internal/testutil"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// newTestService returns a ParticipantService talking to handler
+func newTestService(t *testing.T, handler http.HandlerFunc) *ParticipantService {
+	return NewParticipantService(testutil.NewHTTPClient(t, handler), logger.NewNoOpLogger())
+}
+
+func TestIterateParticipants(t *testing.T) {
+	const count = 130
+
+	var paths []string
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		io.WriteString(w, `<consumers xmlns="http://gsph.sub.com/cust/types">`)
+		for i := offset; i < count && i < offset+limit; i++ {
+			fmt.Fprintf(w, `<consumer><id>%d</id><contractid>9</contractid><name>P%d</name></consumer>`, i+1, i)
+		}
+		io.WriteString(w, `</consumers>`)
+	})
+
+	n := 0
+	for p, err := range svc.IterateParticipants(context.Background(), 9, models.PageOptions{PageSize: 50}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.ID != n+1 || p.ContractID != 9 {
+			t.Fatalf("participant %d: %+v", n, p)
+		}
+		n++
+	}
+
+	if n != count {
+		t.Fatalf("got %d participants, want %d", n, count)
+	}
+	if len(paths) != 3 || paths[0] != fmt.Sprintf(models.ParticipantCustomerMedia, 9) {
+		t.Fatalf("unexpected requests %v", paths)
+	}
+}
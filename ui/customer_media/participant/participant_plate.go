@@ -0,0 +1,135 @@
+package participant
+
+import (
+	"context"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// License plate audit events
+const (
+	auditPlateAdd    = "plate.add"
+	auditPlateRemove = "plate.remove"
+)
+
+// AddLicensePlate registers a license plate on a participant.
+// The plate is normalized; a participant holds up to three plates.
+func (s *ParticipantService) AddLicensePlate(ctx context.Context, contractID, participantID int, plate models.LicensePlate) (*models.ParticipantDetail, error) {
+	plate = models.NormalizePlate(plate.Country, plate.Number)
+	if plate.IsZero() {
+		return nil, errors.NewValidationError("plate", "license plate number is required", nil)
+	}
+
+	detail, err := s.GetParticipantById(ctx, contractID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if detail.HasLicensePlate(plate) {
+		return nil, errors.NewValidationError("plate", "license plate is already registered on the participant", plate.String())
+	}
+	if !detail.AddLicensePlate(plate) {
+		return nil, errors.NewValidationError("plate", "participant has no free license plate slot", plate.String())
+	}
+
+	result, err := s.updateParticipantDetail(ctx, contractID, participantID, *detail)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(auditPlateAdd, contractID, participantID, logger.String("plate", plate.String()))
+
+	return result, nil
+}
+
+// RemoveLicensePlate removes a license plate from a participant
+func (s *ParticipantService) RemoveLicensePlate(ctx context.Context, contractID, participantID int, plate models.LicensePlate) (*models.ParticipantDetail, error) {
+	plate = models.NormalizePlate(plate.Country, plate.Number)
+
+	detail, err := s.GetParticipantById(ctx, contractID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !detail.RemoveLicensePlate(plate) {
+		return nil, errors.NewNotFoundError("license plate not registered on participant", "license plate", plate.String())
+	}
+
+	result, err := s.updateParticipantDetail(ctx, contractID, participantID, *detail)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(auditPlateRemove, contractID, participantID, logger.String("plate", plate.String()))
+
+	return result, nil
+}
+
+// ListLicensePlates returns the license plates registered on a participant
+func (s *ParticipantService) ListLicensePlates(ctx context.Context, contractID, participantID int) ([]models.LicensePlate, error) {
+	detail, err := s.GetParticipantById(ctx, contractID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return detail.LicensePlates(), nil
+}
+
+// FindByLicensePlate finds the participant and contract a license plate is registered on.
+// With an OwnerLookup (see SetOwnerLookup) this is a single query; otherwise every participant
+// of every contract may be inspected, so this is an expensive call.
+func (s *ParticipantService) FindByLicensePlate(ctx context.Context, plate models.LicensePlate) (*models.ParticipantOwner, error) {
+	plate = models.NormalizePlate(plate.Country, plate.Number)
+	s.logger.Info("looking up license plate owner", logger.String("plate", plate.String()))
+
+	owner, err := s.findOwner(ctx, models.ParticipantFilter{LicensePlate: plate.String()}, func(detail *models.ParticipantDetail) bool {
+		return detail.HasLicensePlate(plate)
+	})
+	if err != nil {
+		s.logger.Error("failed to look up license plate owner", logger.String("plate", plate.String()), logger.Error(err))
+		return nil, err
+	}
+
+	if owner == nil {
+		return nil, errors.NewNotFoundError("no participant has this license plate", "license plate", plate.String())
+	}
+
+	return owner, nil
+}
+
+// FindDuplicatePlates returns the license plates registered on more than one participant, across all contracts
+func (s *ParticipantService) FindDuplicatePlates(ctx context.Context) ([]models.PlateDuplicate, error) {
+	s.logger.Info("looking for duplicate license plates")
+
+	owners := make(map[string][]models.ParticipantOwner)
+	plates := make(map[string]models.LicensePlate)
+	var order []string
+
+	err := s.eachParticipant(ctx, func(c models.ContractList, detail *models.ParticipantDetail) bool {
+		for _, plate := range detail.LicensePlates() {
+			if _, seen := owners[plate.Number]; !seen {
+				order = append(order, plate.Number)
+				plates[plate.Number] = plate
+			}
+			owners[plate.Number] = append(owners[plate.Number], models.ParticipantOwner{Contract: c, Participant: *detail})
+		}
+		return true
+	})
+	if err != nil {
+		s.logger.Error("failed to look for duplicate license plates", logger.Error(err))
+		return nil, err
+	}
+
+	var duplicates []models.PlateDuplicate
+	for _, number := range order {
+		if group := owners[number]; len(group) > 1 {
+			duplicates = append(duplicates, models.PlateDuplicate{Plate: plates[number], Owners: group})
+		}
+	}
+
+	s.logger.Info("duplicate license plate lookup finished", logger.Int("duplicates", len(duplicates)))
+
+	return duplicates, nil
+}
@@ -0,0 +1,127 @@
+package participant
+
+import (
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+func TestAddAndRemoveLicensePlate(t *testing.T) {
+	store, svc := newStoreService(t)
+	ctx := context.Background()
+	id := store.Add(models.ParticipantDetail{Participant: models.Participant{ContractID: 7, Name: "Bob"}, LPN1: "xy-9"})
+
+	if _, err := svc.AddLicensePlate(ctx, 7, id, models.LicensePlate{Number: "ab 12-3"}); err != nil {
+		t.Fatal(err)
+	}
+	if d := store.Get(id); d.LPN2 != "AB123" {
+		t.Fatalf("stored %q, want the normalized number", d.LPN2)
+	}
+
+	if _, err := svc.AddLicensePlate(ctx, 7, id, models.LicensePlate{Number: "XY9"}); !errors.IsValidationError(err) {
+		t.Fatalf("plate stored as xy-9 added again: got %v", err)
+	}
+
+	svc.AddLicensePlate(ctx, 7, id, models.LicensePlate{Number: "C1"})
+	if _, err := svc.AddLicensePlate(ctx, 7, id, models.LicensePlate{Number: "C2"}); !errors.IsValidationError(err) {
+		t.Fatalf("fourth plate: got %v", err)
+	}
+
+	if _, err := svc.RemoveLicensePlate(ctx, 7, id, models.LicensePlate{Number: "XY.9"}); err != nil {
+		t.Fatal(err)
+	}
+	plates, err := svc.ListLicensePlates(ctx, 7, id)
+	if err != nil || len(plates) != 2 || plates[0].Number != "AB123" || plates[1].Number != "C1" {
+		t.Fatalf("got %v, %v", plates, err)
+	}
+
+	if _, err := svc.RemoveLicensePlate(ctx, 7, id, models.LicensePlate{Number: "XY9"}); !errors.IsNotFoundError(err) {
+		t.Fatalf("removing a missing plate: got %v", err)
+	}
+}
+
+func TestFindByLicensePlate(t *testing.T) {
+	store, svc := newStoreService(t)
+	store.Add(models.ParticipantDetail{Participant: models.Participant{ContractID: 7, Name: "Bob"}, LPN1: "AB123"})
+	id := store.Add(models.ParticipantDetail{Participant: models.Participant{ContractID: 8, Name: "Al"}, LPN3: "xy-9"})
+
+	owner, err := svc.FindByLicensePlate(context.Background(), models.LicensePlate{Number: "XY 9"})
+	if err != nil || owner.Contract.ID != 8 || *owner.Participant.Participant.ID != id {
+		t.Fatalf("got %+v, %v", owner, err)
+	}
+
+	if _, err := svc.FindByLicensePlate(context.Background(), models.LicensePlate{Number: "ZZ1"}); !errors.IsNotFoundError(err) {
+		t.Fatalf("unknown plate: got %v", err)
+	}
+}
+
+func TestFindDuplicatePlates(t *testing.T) {
+	store, svc := newStoreService(t)
+	store.Add(models.ParticipantDetail{Participant: models.Participant{ContractID: 7, Name: "Bob"}, LPN1: "AB-123", LPN2: "XY9"})
+	store.Add(models.ParticipantDetail{Participant: models.Participant{ContractID: 8, Name: "Al"}, LPN2: "ab123"})
+
+	duplicates, err := svc.FindDuplicatePlates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(duplicates) != 1 || duplicates[0].Plate.Number != "AB123" || len(duplicates[0].Owners) != 2 {
+		t.Fatalf("got %+v", duplicates)
+	}
+}
+
+func TestLicensePlateCountry(t *testing.T) {
+	plate := models.ParseLicensePlate(" d :ab-12 3")
+	if plate.Country != "D" || plate.Number != "AB123" || plate.String() != "D:AB123" {
+		t.Fatalf("parsed %+v", plate)
+	}
+	if bare := models.ParseLicensePlate("ab.123"); bare.Country != "" || bare.Number != "AB123" {
+		t.Fatalf("parsed %+v", bare)
+	}
+
+	for _, tc := range []struct {
+		other models.LicensePlate
+		want  bool
+	}{
+		{models.NormalizePlate("d", "AB 123"), true},
+		{models.NormalizePlate("", "AB123"), true},
+		{models.NormalizePlate("F", "AB123"), false},
+		{models.NormalizePlate("D", "AB124"), false},
+	} {
+		if got := plate.Matches(tc.other); got != tc.want {
+			t.Errorf("%v matches %v: got %v", plate, tc.other, got)
+		}
+	}
+}
+
+func TestAddLicensePlateStoresNumberOnly(t *testing.T) {
+	store, svc := newStoreService(t)
+	id := store.Add(models.ParticipantDetail{Participant: models.Participant{ContractID: 7, Name: "Bob"}})
+
+	if _, err := svc.AddLicensePlate(context.Background(), 7, id, models.LicensePlate{Country: "ch ", Number: "zh-1 23"}); err != nil {
+		t.Fatal(err)
+	}
+	if d := store.Get(id); d.LPN1 != "ZH123" {
+		t.Fatalf("stored %q, want the normalized number", d.LPN1)
+	}
+
+	owner, err := svc.FindByLicensePlate(context.Background(), models.ParseLicensePlate("CH:ZH123"))
+	if err != nil || *owner.Participant.Participant.ID != id {
+		t.Fatalf("got %+v, %v", owner, err)
+	}
+}
+
+func TestRemovedLicensePlateIsSent(t *testing.T) {
+	out, err := xml.Marshal(models.ParticipantDetail{LPN1: "AB123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, slot := range []string{"<lpn2></lpn2>", "<lpn3></lpn3>"} {
+		if !strings.Contains(string(out), slot) {
+			t.Fatalf("cleared slot %s not sent: %s", slot, out)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package participant
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// auditPresenceReset is logged on every presence reset
+const auditPresenceReset = "presence.reset"
+
+// GetPresence returns the anti-passback presence state of a participant
+func (s *ParticipantService) GetPresence(ctx context.Context, contractID, participantID int) (models.PresenceState, error) {
+	detail, err := s.GetParticipantById(ctx, contractID, participantID)
+	if err != nil {
+		return models.PresenceAbsent, err
+	}
+
+	return detail.Present, nil
+}
+
+// ResetPresence sets the presence state of a participant, e.g. after a gate fault left it marked inside.
+// The operator identifier is required and recorded in the audit log.
+func (s *ParticipantService) ResetPresence(ctx context.Context, contractID, participantID int, state models.PresenceState, operator string) (*models.ParticipantDetail, error) {
+	if operator == "" {
+		return nil, errors.NewValidationError("operator", "operator identifier is required", nil)
+	}
+	if state != models.PresenceAbsent && state != models.PresencePresent {
+		return nil, errors.NewValidationError("state", "invalid presence state", int(state))
+	}
+
+	detail, err := s.GetParticipantById(ctx, contractID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := detail.Present
+	detail.Present = state
+
+	result, err := s.updateParticipantDetail(ctx, contractID, participantID, *detail)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(auditPresenceReset, contractID, participantID,
+		logger.String("operator", operator),
+		logger.String("from_state", previous.String()),
+		logger.String("to_state", state.String()),
+	)
+
+	return result, nil
+}
+
+// CountPresent returns how many participants of a contract are currently inside,
+// read from the presence counter the ZR server keeps on the contract
+func (s *ParticipantService) CountPresent(ctx context.Context, contractID int) (int, error) {
+	path := fmt.Sprintf(models.ContractCustomerMediaByID, contractID)
+	var contract models.ContractDetail
+
+	if err := s.httpClient.DoXMLRequest(ctx, http.MethodGet, path, nil, &contract); err != nil {
+		s.logger.Error("failed to count contract presence", logger.Int("contract_id", contractID), logger.Error(err))
+		return 0, err
+	}
+
+	s.logger.Info("contract presence counted", logger.Int("contract_id", contractID), logger.Int("inside", contract.Present))
+
+	return contract.Present, nil
+}
@@ -0,0 +1,59 @@
+package participant
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+func TestResetPresence(t *testing.T) {
+	store, svc := newStoreService(t)
+	ctx := context.Background()
+	id := store.Add(models.ParticipantDetail{Participant: models.Participant{ContractID: 7, Name: "Bob"}, Present: models.PresencePresent})
+
+	if _, err := svc.ResetPresence(ctx, 7, id, models.PresenceAbsent, "op-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Absent is 0 and must still be sent
+	if len(store.Puts) != 1 || store.Get(id).Present != models.PresenceAbsent {
+		t.Fatalf("presence not reset: %+v", store.Puts)
+	}
+	if state, err := svc.GetPresence(ctx, 7, id); err != nil || state != models.PresenceAbsent {
+		t.Fatalf("got %v, %v", state, err)
+	}
+
+	for _, tc := range []struct {
+		state    models.PresenceState
+		operator string
+	}{
+		{models.PresenceAbsent, ""},
+		{models.PresenceState(5), "op-1"},
+	} {
+		if _, err := svc.ResetPresence(ctx, 7, id, tc.state, tc.operator); !errors.IsValidationError(err) {
+			t.Errorf("state %d, operator %q: got %v", tc.state, tc.operator, err)
+		}
+	}
+}
+
+func TestCountPresent(t *testing.T) {
+	var requests []string
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		io.WriteString(w, `<contractDetail xmlns="http://gsph.sub.com/cust/types">`+
+			`<contract><id>7</id><name>Acme</name></contract><counting>5</counting><present>2</present></contractDetail>`)
+	})
+
+	inside, err := svc.CountPresent(context.Background(), 7)
+	if err != nil || inside != 2 {
+		t.Fatalf("got %d, %v", inside, err)
+	}
+	if len(requests) != 1 || requests[0] != fmt.Sprintf(models.ContractCustomerMediaByID, 7) {
+		t.Fatalf("requests %v, want the contract detail only", requests)
+	}
+}
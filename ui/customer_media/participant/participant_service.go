@@ -2,20 +2,26 @@ package participant
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"sync"
 
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
 	internalhttp "github.com/yassine-manai/go_zr_sdk/internal/http"
 	"github.com/yassine-manai/go_zr_sdk/internal/logger"
 	"github.com/yassine-manai/go_zr_sdk/models"
 )
 
-// Service handles contract operations
+// Service handles participant (consumer) operations
 type ParticipantService struct {
 	httpClient *internalhttp.Client
 	logger     logger.Logger
+
+	mu     sync.RWMutex
+	owners OwnerLookup // Finds card and plate owners, e.g. in the ZR database
 }
 
-// New creates a new contract service
+// New creates a new participant service
 func NewParticipantService(httpClient *internalhttp.Client, log logger.Logger) *ParticipantService {
 	return &ParticipantService{
 		httpClient: httpClient,
@@ -23,24 +29,241 @@ func NewParticipantService(httpClient *internalhttp.Client, log logger.Logger) *
 	}
 }
 
-// CreateContract creates a new contract
-func (s *ParticipantService) CreateParticipant(ctx context.Context, req models.ContractRequest) (*models.ContractDetail, error) {
-	s.logger.Info("creating contract", logger.String("name", req.Name), logger.String("valid_from", req.ValidFrom), logger.String("valid_until", req.ValidUntil))
+// SetOwnerLookup sets the source used by FindByCard and FindByLicensePlate instead of walking
+// every participant. The client sets it to the ZR database when one is configured.
+func (s *ParticipantService) SetOwnerLookup(lookup OwnerLookup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.owners = lookup
+}
+
+func (s *ParticipantService) ownerLookup() OwnerLookup {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.owners
+}
+
+// CreateParticipant creates a new participant under a contract
+func (s *ParticipantService) CreateParticipant(ctx context.Context, req models.ParticipantRequest) (*models.ParticipantDetail, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("creating participant", logger.Int("contract_id", req.ContractID), logger.String("name", req.Name), logger.String("valid_from", req.ValidFrom), logger.String("valid_until", req.ValidUntil))
 
 	// Convert to XML structure
-	contractDetail := req.ToXML()
+	participantDetail := req.ToXML()
+	var result models.ParticipantDetail
 
-	var result models.ContractDetail
+	path := fmt.Sprintf(models.ParticipantCustomerMedia, req.ContractID)
 
-	// Execute with retry
-	err := s.httpClient.DoXMLRequest(ctx, http.MethodPost, models.ContractCustomerMedia, &contractDetail, &result)
+	// Execute request
+	err := s.httpClient.DoXMLRequest(
+		ctx,
+		http.MethodPost,
+		path,
+		&participantDetail,
+		&result,
+	)
 
 	if err != nil {
-		s.logger.Error("failed to create contract", logger.String("name", req.Name), logger.Error(err))
+		s.logger.Error("failed to create participant", logger.Int("contract_id", req.ContractID), logger.String("name", req.Name), logger.Error(err))
 		return nil, err
 	}
 
-	s.logger.Info("contract created successfully", logger.Int("id", *result.Contract.ID), logger.String("name", result.Contract.Name))
+	fields := []logger.Field{logger.Int("contract_id", req.ContractID), logger.String("name", result.Participant.Name)}
+	if result.Participant.ID != nil {
+		fields = append(fields, logger.Int("id", *result.Participant.ID))
+	}
+	s.logger.Info("participant created successfully", fields...)
 
 	return &result, nil
 }
+
+// GetParticipantById retrieves a participant of a contract by ID
+func (s *ParticipantService) GetParticipantById(ctx context.Context, contractID, participantID int) (*models.ParticipantDetail, error) {
+	s.logger.Info("getting participant", logger.Int("contract_id", contractID), logger.Int("participant_id", participantID))
+
+	path := fmt.Sprintf(models.ParticipantCustomerMediaByID, contractID, participantID)
+	var result models.ParticipantDetail
+
+	err := s.httpClient.DoXMLRequest(
+		ctx,
+		http.MethodGet, path,
+		nil, &result,
+	)
+
+	if err != nil {
+		s.logger.Error("failed to get participant", logger.Int("contract_id", contractID), logger.Int("participant_id", participantID), logger.Error(err))
+		return nil, err
+	}
+
+	if result.Participant.ID == nil {
+		result.Participant.ID = &participantID
+	}
+	if result.Participant.ContractID == 0 {
+		result.Participant.ContractID = contractID
+	}
+
+	s.logger.Info("participant retrieved successfully", logger.Int("contract_id", contractID), logger.Int("participant_id", participantID), logger.String("name", result.Participant.Name))
+
+	return &result, nil
+}
+
+// GetParticipantList retrieves all participants of a contract
+func (s *ParticipantService) GetParticipantList(ctx context.Context, contractID int) (*models.Participants, error) {
+	s.logger.Info("getting participant list", logger.Int("contract_id", contractID))
+
+	path := fmt.Sprintf(models.ParticipantCustomerMedia, contractID)
+	var result models.Participants
+
+	err := s.httpClient.DoXMLRequest(
+		ctx,
+		http.MethodGet, path,
+		nil, &result,
+	)
+
+	if err != nil {
+		s.logger.Error("failed to get participants", logger.Int("contract_id", contractID), logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("participants retrieved successfully", logger.Int("contract_id", contractID), logger.Int("Count", len(result.Participant)))
+
+	return &result, nil
+}
+
+// UpdateParticipant updates an existing participant.
+// The participant is read first and only the fields set in req are changed; an identical
+// participant is left untouched.
+func (s *ParticipantService) UpdateParticipant(ctx context.Context, req models.ParticipantRequest) (*models.ParticipantDetail, error) {
+	if req.ID == nil {
+		return nil, errors.NewValidationError("id", "participant ID is required for update", nil)
+	}
+	if req.ContractID <= 0 {
+		return nil, errors.NewValidationError("contractId", "contract ID is required", req.ContractID)
+	}
+
+	existing, err := s.GetParticipantById(ctx, req.ContractID, *req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, changed := mergeParticipant(*existing, req)
+	if !changed {
+		s.logger.Info("participant unchanged", logger.Int("contract_id", req.ContractID), logger.Int("participant_id", *req.ID))
+		return existing, nil
+	}
+
+	return s.updateParticipantDetail(ctx, req.ContractID, *req.ID, merged)
+}
+
+// mergeParticipant applies the fields set in req to an existing participant and reports whether anything changed
+func mergeParticipant(existing models.ParticipantDetail, req models.ParticipantRequest) (models.ParticipantDetail, bool) {
+	merged := existing
+	changed := false
+
+	if req.Name != "" && req.Name != existing.Participant.Name {
+		merged.Participant.Name = req.Name
+		changed = true
+	}
+
+	if req.FirstName != "" && req.FirstName != existing.Participant.FirstName {
+		merged.Participant.FirstName = req.FirstName
+		changed = true
+	}
+
+	if req.ValidFrom != "" && !models.SameDate(req.ValidFrom, existing.Participant.ValidFrom) {
+		merged.Participant.ValidFrom = req.ValidFrom
+		changed = true
+	}
+
+	if req.ValidUntil != "" && !models.SameDate(req.ValidUntil, existing.Participant.ValidUntil) {
+		merged.Participant.ValidUntil = req.ValidUntil
+		changed = true
+	}
+
+	if req.LicensePlate != "" {
+		if plate := models.ParseLicensePlate(req.LicensePlate).Number; plate != existing.LPN1 {
+			merged.LPN1 = plate
+			changed = true
+		}
+	}
+
+	if req.CardNumber != "" || req.TicketClass != 0 {
+		card := models.Identification{ValidFrom: merged.Participant.ValidFrom, ValidUntil: merged.Participant.ValidUntil}
+		if existing.Identification != nil {
+			card = *existing.Identification
+		}
+
+		if req.CardNumber != "" && req.CardNumber != card.CardNo {
+			card.CardNo = req.CardNumber
+			changed = true
+		}
+		if req.TicketClass != 0 && req.TicketClass != card.CardClass {
+			card.CardClass = req.TicketClass
+			changed = true
+		}
+
+		merged.Identification = &card
+	}
+
+	if req.Status != nil && *req.Status != existing.Status {
+		merged.Status = *req.Status
+		changed = true
+	}
+
+	return merged, changed
+}
+
+// updateParticipantDetail replaces the detail of an existing participant
+func (s *ParticipantService) updateParticipantDetail(ctx context.Context, contractID, participantID int, participantDetail models.ParticipantDetail) (*models.ParticipantDetail, error) {
+	s.logger.Info("updating participant", logger.Int("contract_id", contractID), logger.Int("participant_id", participantID), logger.String("name", participantDetail.Participant.Name))
+
+	var result models.ParticipantDetail
+
+	path := fmt.Sprintf(models.ParticipantCustomerMediaDetail, contractID, participantID)
+
+	// Execute request
+	err := s.httpClient.DoXMLRequest(
+		ctx,
+		http.MethodPut,
+		path,
+		&participantDetail,
+		&result,
+	)
+
+	if err != nil {
+		s.logger.Error("failed to update participant", logger.Int("contract_id", contractID), logger.Int("participant_id", participantID), logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("participant updated successfully", logger.Int("contract_id", contractID), logger.Int("participant_id", participantID), logger.String("name", result.Participant.Name))
+
+	return &result, nil
+}
+
+// DeleteParticipant deletes a participant of a contract by ID
+func (s *ParticipantService) DeleteParticipant(ctx context.Context, contractID, participantID int) error {
+	s.logger.Info("deleting participant", logger.Int("contract_id", contractID), logger.Int("participant_id", participantID))
+
+	path := fmt.Sprintf(models.ParticipantCustomerMediaByID, contractID, participantID)
+
+	// Execute DELETE request (no response body expected on success)
+	err := s.httpClient.DoXMLRequest(
+		ctx,
+		http.MethodDelete,
+		path,
+		nil,
+		nil, // No result expected on 200 OK
+	)
+
+	if err != nil {
+		s.logger.Error("failed to delete participant", logger.Int("contract_id", contractID), logger.Int("participant_id", participantID), logger.Error(err))
+		return err
+	}
+
+	s.logger.Info("participant deleted successfully", logger.Int("contract_id", contractID), logger.Int("participant_id", participantID))
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package participant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/testutil"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+func newStoreService(t *testing.T) (*testutil.ParticipantStore, *ParticipantService) {
+	store := testutil.NewParticipantStore()
+	return store, newTestService(t, store.ServeHTTP)
+}
+
+func TestParticipantCRUD(t *testing.T) {
+	store, svc := newStoreService(t)
+	ctx := context.Background()
+
+	created, err := svc.CreateParticipant(ctx, models.ParticipantRequest{
+		ContractID: 7, Name: "Bob", ValidFrom: "2026-01-01", ValidUntil: "2027-01-01", CardNumber: "111",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := *created.Participant.ID
+
+	got, err := svc.GetParticipantById(ctx, 7, id)
+	if err != nil || got.Participant.Name != "Bob" || got.Identification.CardNo != "111" {
+		t.Fatalf("got %+v, err %v", got, err)
+	}
+
+	list, err := svc.GetParticipantList(ctx, 7)
+	if err != nil || len(list.Participant) != 1 || list.Participant[0].ID != id {
+		t.Fatalf("list %+v, err %v", list, err)
+	}
+
+	if err := svc.DeleteParticipant(ctx, 7, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetParticipantById(ctx, 7, id); !errors.IsNotFoundError(err) {
+		t.Fatalf("deleted participant: got %v", err)
+	}
+	if len(store.Items) != 0 {
+		t.Fatalf("%d participants left", len(store.Items))
+	}
+}
+
+func TestCreateParticipantValidates(t *testing.T) {
+	store, svc := newStoreService(t)
+
+	_, err := svc.CreateParticipant(context.Background(), models.ParticipantRequest{ContractID: 7})
+	if !errors.IsValidationError(err) || len(store.Items) != 0 {
+		t.Fatalf("got %v, %d participants", err, len(store.Items))
+	}
+}
+
+func TestUpdateParticipantMergesSetFields(t *testing.T) {
+	store, svc := newStoreService(t)
+	id := store.Add(models.ParticipantDetail{
+		Participant:    models.Participant{ContractID: 7, Name: "Bob", FirstName: "B", ValidFrom: "2026-01-01", ValidUntil: "2027-01-01"},
+		Identification: &models.Identification{CardNo: "111", CardClass: 3},
+		LPN1:           "AB123",
+		Memo:           "kept",
+		Status:         models.ParticipantStatusBlocked,
+	})
+
+	active := models.ParticipantStatusActive
+	got, err := svc.UpdateParticipant(context.Background(), models.ParticipantRequest{
+		ID: &id, ContractID: 7, ValidUntil: "2028-01-01", Status: &active,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := store.Get(id)
+	if got.Participant.ValidUntil != "2028-01-01" || d.Status != models.ParticipantStatusActive {
+		t.Fatalf("update not applied: %+v", d)
+	}
+	if d.Participant.Name != "Bob" || d.Participant.FirstName != "B" || d.Memo != "kept" || d.LPN1 != "AB123" ||
+		d.Identification == nil || d.Identification.CardNo != "111" || d.Identification.CardClass != 3 {
+		t.Fatalf("unset fields were not kept: %+v %+v", d, d.Identification)
+	}
+}
+
+func TestUpdateParticipantUnchanged(t *testing.T) {
+	store, svc := newStoreService(t)
+	id := store.Add(models.ParticipantDetail{
+		Participant: models.Participant{ContractID: 7, Name: "Bob", ValidFrom: "2026-01-01+01:00"},
+	})
+
+	if _, err := svc.UpdateParticipant(context.Background(), models.ParticipantRequest{ID: &id, ContractID: 7, Name: "Bob", ValidFrom: "2026-01-01"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.Puts) != 0 {
+		t.Fatalf("%d PUTs for an identical participant", len(store.Puts))
+	}
+
+	if _, err := svc.UpdateParticipant(context.Background(), models.ParticipantRequest{ContractID: 7, Name: "Bob"}); !errors.IsValidationError(err) {
+		t.Fatalf("missing ID: got %v", err)
+	}
+}
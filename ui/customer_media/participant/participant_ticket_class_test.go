@@ -0,0 +1,70 @@
+package participant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+func TestAssignTicketClass(t *testing.T) {
+	store, svc := newStoreService(t)
+	ctx := context.Background()
+	id := newCardParticipant(store, "111")
+
+	if _, err := svc.AssignTicketClass(ctx, 7, id, 4); err != nil {
+		t.Fatal(err)
+	}
+	if card := store.Get(id).Identification; card.CardClass != 4 || card.CardNo != "111" {
+		t.Fatalf("got card %+v", card)
+	}
+
+	// Assigning the current class again is a no-op
+	if _, err := svc.AssignTicketClass(ctx, 7, id, 4); err != nil || len(store.Puts) != 1 {
+		t.Fatalf("%d PUTs, err %v", len(store.Puts), err)
+	}
+
+	if _, err := svc.AssignTicketClass(ctx, 7, id, 0); !errors.IsValidationError(err) {
+		t.Fatalf("class 0: got %v", err)
+	}
+
+	bare := store.Add(models.ParticipantDetail{Participant: models.Participant{ContractID: 7, Name: "Al"}})
+	if _, err := svc.AssignTicketClass(ctx, 7, bare, 4); !errors.IsValidationError(err) {
+		t.Fatalf("participant without card: got %v", err)
+	}
+	if store.Get(bare).Identification != nil || len(store.Puts) != 1 {
+		t.Fatal("card created without a card number")
+	}
+}
+
+func TestAssignTicketClassToParticipants(t *testing.T) {
+	store, svc := newStoreService(t)
+	a := store.Add(models.ParticipantDetail{Participant: models.Participant{ContractID: 7, Name: "Bob"}, Identification: &models.Identification{CardNo: "110", CardClass: 1}})
+	b := store.Add(models.ParticipantDetail{Participant: models.Participant{ContractID: 7, Name: "Al"}, Identification: &models.Identification{CardNo: "111", CardClass: 2}})
+	bare := store.Add(models.ParticipantDetail{Participant: models.Participant{ContractID: 7, Name: "Joe"}})
+	other := store.Add(models.ParticipantDetail{Participant: models.Participant{ContractID: 8, Name: "Eve"}, Identification: &models.Identification{CardNo: "112", CardClass: 2}})
+
+	report, err := svc.AssignTicketClassToParticipants(context.Background(), 7, 4, models.BulkOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Succeeded != 2 || report.Failed != 1 {
+		t.Fatalf("report %+v", report)
+	}
+
+	for _, id := range []int{a, b} {
+		if card := store.Get(id).Identification; card == nil || card.CardClass != 4 {
+			t.Fatalf("participant %d: card %+v", id, card)
+		}
+	}
+	if store.Get(b).Identification.CardNo != "111" {
+		t.Fatal("card number lost")
+	}
+	if store.Get(bare).Identification != nil {
+		t.Fatal("card created for a participant without card")
+	}
+	if store.Get(other).Identification.CardClass != 2 {
+		t.Fatal("participant of another contract updated")
+	}
+}
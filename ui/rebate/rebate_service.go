@@ -0,0 +1,138 @@
+package rebate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	internalhttp "github.com/yassine-manai/go_zr_sdk/internal/http"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// Service handles rebate operations
+type RebateService struct {
+	httpClient *internalhttp.Client
+	logger     logger.Logger
+}
+
+// NewRebateService creates a new rebate service
+func NewRebateService(httpClient *internalhttp.Client, log logger.Logger) *RebateService {
+	return &RebateService{
+		httpClient: httpClient,
+		logger:     log,
+	}
+}
+
+// GetRebateList retrieves all rebate definitions
+func (s *RebateService) GetRebateList(ctx context.Context) (*models.Rebates, error) {
+	s.logger.Info("getting all rebates from zr")
+
+	var result models.Rebates
+
+	err := s.httpClient.DoXMLRequest(
+		ctx,
+		http.MethodGet, models.RebateCustomerMedia,
+		nil, &result,
+	)
+
+	if err != nil {
+		s.logger.Error("failed to get rebates", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("rebates retrieved successfully", logger.Int("Count", len(result.Rebate)))
+
+	return &result, nil
+}
+
+// GetRebateById retrieves a rebate definition by ID
+func (s *RebateService) GetRebateById(ctx context.Context, rebateID int) (*models.Rebate, error) {
+	s.logger.Info("getting rebate", logger.Int("rebate_id", rebateID))
+
+	path := fmt.Sprintf(models.RebateCustomerMediaByID, rebateID)
+	var result models.Rebate
+
+	err := s.httpClient.DoXMLRequest(
+		ctx,
+		http.MethodGet, path,
+		nil, &result,
+	)
+
+	if err != nil {
+		s.logger.Error("failed to get rebate", logger.Int("rebate_id", rebateID), logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("rebate retrieved successfully", logger.Int("rebate_id", rebateID), logger.String("name", result.Name), logger.String("kind", string(result.Kind)))
+
+	return &result, nil
+}
+
+// ApplyToTicket applies a rebate to a parking ticket (ticket validation)
+func (s *RebateService) ApplyToTicket(ctx context.Context, rebateID int, ticketNo string) (*models.RebateApplication, error) {
+	if ticketNo == "" {
+		return nil, errors.NewValidationError("ticketNo", "ticket number is required", nil)
+	}
+
+	path := fmt.Sprintf(models.RebateApplyTicket, url.PathEscape(ticketNo))
+	application := models.RebateApplication{RebateID: rebateID, TicketNo: ticketNo}
+
+	return s.apply(ctx, path, application, logger.String("ticket_no", ticketNo))
+}
+
+// ApplyToContract applies a rebate to a contract
+func (s *RebateService) ApplyToContract(ctx context.Context, rebateID, contractID int) (*models.RebateApplication, error) {
+	if contractID <= 0 {
+		return nil, errors.NewValidationError("contractId", "contract ID is required", contractID)
+	}
+
+	path := fmt.Sprintf(models.RebateApplyContract, contractID)
+	application := models.RebateApplication{RebateID: rebateID, ContractID: contractID}
+
+	return s.apply(ctx, path, application, logger.Int("contract_id", contractID))
+}
+
+// apply checks that the rebate is usable today and posts the application
+func (s *RebateService) apply(ctx context.Context, path string, application models.RebateApplication, target logger.Field) (*models.RebateApplication, error) {
+	rebate, err := s.GetRebateById(ctx, application.RebateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !rebate.Kind.IsValid() {
+		return nil, errors.NewValidationError("kind", "unsupported rebate kind", string(rebate.Kind))
+	}
+	if !rebate.IsValidOn(time.Now()) {
+		return nil, errors.NewValidationError("rebateId", "rebate is not valid today", application.RebateID)
+	}
+
+	s.logger.Info("applying rebate", logger.Int("rebate_id", application.RebateID), logger.String("kind", string(rebate.Kind)), target)
+
+	var result models.RebateApplication
+
+	err = s.httpClient.DoXMLRequest(
+		ctx,
+		http.MethodPost,
+		path,
+		&application,
+		&result,
+	)
+
+	if err != nil {
+		s.logger.Error("failed to apply rebate", logger.Int("rebate_id", application.RebateID), target, logger.Error(err))
+		return nil, err
+	}
+
+	// Servers answering with an empty body still applied the rebate
+	if result.RebateID == 0 {
+		result = application
+	}
+
+	s.logger.Info("rebate applied successfully", logger.Int("rebate_id", application.RebateID), target)
+
+	return &result, nil
+}
@@ -0,0 +1,115 @@
+package rebate
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/internal/testutil"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// newTestService returns a RebateService talking to handler
+func newTestService(t *testing.T, handler http.HandlerFunc) *RebateService {
+	return NewRebateService(testutil.NewHTTPClient(t, handler), logger.NewNoOpLogger())
+}
+
+// rebateServer serves the rebates by ID and records the applications posted to it
+func rebateServer(rebates map[int]string, applied *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var application models.RebateApplication
+			body, _ := io.ReadAll(r.Body)
+			xml.Unmarshal(body, &application)
+			*applied = append(*applied, fmt.Sprintf("%s %d", r.URL.Path, application.RebateID))
+			return
+		}
+
+		var id int
+		fmt.Sscanf(r.URL.Path, "/CustomerMediaWebService/rebates/%d", &id)
+		rebate, ok := rebates[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `<rebate xmlns="http://gsph.sub.com/cust/types"><id>%d</id>%s</rebate>`, id, rebate)
+	}
+}
+
+func TestApplyRebate(t *testing.T) {
+	var applied []string
+	svc := newTestService(t, rebateServer(map[int]string{
+		1: `<name>Cinema</name><kind>timeCredit</kind><value>90</value>`,
+		2: `<name>Expired</name><kind>amount</kind><value currency="EUR">5.00</value><xValidUntil>2020-01-01</xValidUntil>`,
+		3: `<name>Odd</name><kind>voucher</kind><value>1</value>`,
+	}, &applied))
+	ctx := context.Background()
+
+	rebate, err := svc.GetRebateById(ctx, 1)
+	if err != nil || rebate.TimeCredit() != 90*time.Minute {
+		t.Fatalf("got %+v, %v", rebate, err)
+	}
+
+	result, err := svc.ApplyToTicket(ctx, 1, "T 42")
+	if err != nil || result.TicketNo != "T 42" || result.RebateID != 1 {
+		t.Fatalf("got %+v, %v", result, err)
+	}
+	if _, err := svc.ApplyToContract(ctx, 1, 7); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(applied) != "[/CustomerMediaWebService/tickets/T 42/rebates 1 /CustomerMediaWebService/contracts/7/rebates 1]" {
+		t.Fatalf("applied %q", applied)
+	}
+
+	for _, tc := range []struct {
+		name string
+		err  error
+	}{
+		{"expired", func() error { _, err := svc.ApplyToTicket(ctx, 2, "T1"); return err }()},
+		{"unknown kind", func() error { _, err := svc.ApplyToTicket(ctx, 3, "T1"); return err }()},
+		{"no ticket", func() error { _, err := svc.ApplyToTicket(ctx, 1, ""); return err }()},
+		{"no contract", func() error { _, err := svc.ApplyToContract(ctx, 1, 0); return err }()},
+	} {
+		if !errors.IsValidationError(tc.err) {
+			t.Errorf("%s: got %v", tc.name, tc.err)
+		}
+	}
+	if len(applied) != 2 {
+		t.Fatalf("invalid rebates were applied: %q", applied)
+	}
+
+	if _, err := svc.ApplyToTicket(ctx, 9, "T1"); !errors.IsNotFoundError(err) {
+		t.Fatalf("unknown rebate: got %v", err)
+	}
+}
+
+func TestRebateKind(t *testing.T) {
+	for kind, valid := range map[models.RebateKind]bool{
+		models.RebateKindPercentage: true,
+		models.RebateKindAmount:     true,
+		models.RebateKindTimeCredit: true,
+		"voucher":                   false,
+		"":                          false,
+	} {
+		if kind.IsValid() != valid {
+			t.Errorf("%q: IsValid %v", kind, !valid)
+		}
+	}
+
+	amount := models.Rebate{Kind: models.RebateKindAmount, Value: models.NewMoney(9000, "EUR")}
+	if credit := amount.TimeCredit(); credit != 0 {
+		t.Fatalf("amount rebate has a time credit of %v", credit)
+	}
+	if got := amount.Amount(); got != models.NewMoney(9000, "EUR") {
+		t.Fatalf("amount rebate: got %v", got)
+	}
+	if got := (models.Rebate{Kind: models.RebateKindTimeCredit, Value: models.NewMoney(9000, "")}).Amount(); got != (models.Money{}) {
+		t.Fatalf("time credit rebate has an amount of %v", got)
+	}
+}
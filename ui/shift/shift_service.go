@@ -0,0 +1,144 @@
+package shift
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	internalhttp "github.com/yassine-manai/go_zr_sdk/internal/http"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// Service handles cashier shift operations
+type ShiftService struct {
+	httpClient *internalhttp.Client
+	logger     logger.Logger
+}
+
+// NewShiftService creates a new shift service
+func NewShiftService(httpClient *internalhttp.Client, log logger.Logger) *ShiftService {
+	return &ShiftService{
+		httpClient: httpClient,
+		logger:     log,
+	}
+}
+
+// ListShifts retrieves the shifts matching the filter (device and start date range)
+func (s *ShiftService) ListShifts(ctx context.Context, filter models.ShiftFilter) (*models.Shifts, error) {
+	s.logger.Info("listing shifts", logger.Int("device_id", filter.DeviceID), logger.Any("from", filter.From), logger.Any("until", filter.Until))
+
+	query := url.Values{}
+	if filter.DeviceID != 0 {
+		query.Set("deviceId", strconv.Itoa(filter.DeviceID))
+	}
+	if !filter.From.IsZero() {
+		query.Set("from", filter.From.Format(time.RFC3339))
+	}
+	if !filter.Until.IsZero() {
+		query.Set("until", filter.Until.Format(time.RFC3339))
+	}
+
+	shifts, err := s.getShifts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-apply the filter, for servers ignoring the query parameters
+	matched := shifts.Shift[:0]
+	for _, shift := range shifts.Shift {
+		ok, err := filter.Matches(shift)
+		if err != nil {
+			s.logger.Error("failed to filter shifts", logger.Int("shift_id", shift.ID), logger.Error(err))
+			return nil, errors.NewSDKError(errors.ErrorTypeInternal, "invalid shift start", err)
+		}
+		if ok {
+			matched = append(matched, shift)
+		}
+	}
+	shifts.Shift = matched
+
+	s.logger.Info("shifts listed successfully", logger.Int("Count", len(shifts.Shift)))
+
+	return shifts, nil
+}
+
+// GetOpenShifts retrieves the shifts currently open, on one device or on all devices (deviceID 0)
+func (s *ShiftService) GetOpenShifts(ctx context.Context, deviceID int) (*models.Shifts, error) {
+	s.logger.Info("getting open shifts", logger.Int("device_id", deviceID))
+
+	query := url.Values{}
+	query.Set("status", string(models.ShiftStatusOpen))
+	if deviceID != 0 {
+		query.Set("deviceId", strconv.Itoa(deviceID))
+	}
+
+	shifts, err := s.getShifts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := models.ShiftFilter{DeviceID: deviceID}
+	open := shifts.Shift[:0]
+	for _, shift := range shifts.Shift {
+		// Without a date range, Matches only compares the device and cannot fail
+		if ok, _ := filter.Matches(shift); shift.IsOpen() && ok {
+			open = append(open, shift)
+		}
+	}
+	shifts.Shift = open
+
+	s.logger.Info("open shifts retrieved successfully", logger.Int("Count", len(shifts.Shift)))
+
+	return shifts, nil
+}
+
+// GetShiftDetail retrieves a shift with its totals per payment type
+func (s *ShiftService) GetShiftDetail(ctx context.Context, shiftID int) (*models.ShiftDetail, error) {
+	s.logger.Info("getting shift detail", logger.Int("shift_id", shiftID))
+
+	path := fmt.Sprintf(models.ShiftCustomerMediaDetail, shiftID)
+	var result models.ShiftDetail
+
+	err := s.httpClient.DoXMLRequest(
+		ctx,
+		http.MethodGet, path,
+		nil, &result,
+	)
+
+	if err != nil {
+		s.logger.Error("failed to get shift detail", logger.Int("shift_id", shiftID), logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("shift detail retrieved successfully", logger.Int("shift_id", shiftID), logger.Int("payment_types", len(result.Payments)))
+
+	return &result, nil
+}
+
+// getShifts fetches the shift list with the given query parameters
+func (s *ShiftService) getShifts(ctx context.Context, query url.Values) (*models.Shifts, error) {
+	path := models.ShiftCustomerMedia
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var result models.Shifts
+
+	err := s.httpClient.DoXMLRequest(
+		ctx,
+		http.MethodGet, path,
+		nil, &result,
+	)
+
+	if err != nil {
+		s.logger.Error("failed to get shifts", logger.Error(err))
+		return nil, err
+	}
+
+	return &result, nil
+}
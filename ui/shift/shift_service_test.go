@@ -0,0 +1,141 @@
+package shift
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/internal/testutil"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// newTestService returns a ShiftService talking to handler
+func newTestService(t *testing.T, handler http.HandlerFunc) *ShiftService {
+	return NewShiftService(testutil.NewHTTPClient(t, handler), logger.NewNoOpLogger())
+}
+
+func TestGetShiftDetailDecimalAmounts(t *testing.T) {
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `<shiftDetail xmlns="http://gsph.sub.com/cust/types">`+
+			`<shift><id>3</id><deviceId>12</deviceId><start>2026-03-01</start><status>closed</status><total currency="EUR">20.00</total></shift>`+
+			`<payments>`+
+			`<payment><paymentType>cash</paymentType><count>2</count><amount currency="EUR">12.50</amount></payment>`+
+			`<payment><paymentType>card</paymentType><count>1</count><amount currency="EUR">7.5</amount></payment>`+
+			`</payments></shiftDetail>`)
+	})
+
+	detail, err := svc.GetShiftDetail(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Shift.Total != models.NewMoney(2000, "EUR") {
+		t.Fatalf("total %+v", detail.Shift.Total)
+	}
+	if detail.Payments[0].Amount.Amount != 1250 || detail.Payments[1].Amount.Amount != 750 {
+		t.Fatalf("payments %+v", detail.Payments)
+	}
+
+	var out bytes.Buffer
+	if err := (models.ShiftDetails{*detail}).WriteCSV(&out); err != nil {
+		t.Fatal(err)
+	}
+	want := "shiftId,deviceId,deviceName,cashierId,start,end,status,paymentType,count,amount,currency\n" +
+		"3,12,,,2026-03-01,,closed,cash,2,12.50,EUR\n" +
+		"3,12,,,2026-03-01,,closed,card,1,7.50,EUR\n"
+	if out.String() != want {
+		t.Fatalf("CSV:\n%s", out.String())
+	}
+}
+
+func TestMoneyText(t *testing.T) {
+	for text, want := range map[string]models.MinorUnits{
+		"12.50":   1250,
+		"12.5":    1250,
+		"12":      1200,
+		" -3.05 ": -305,
+		"+0.99":   99,
+		"1.500":   150,
+		"":        0,
+	} {
+		var got models.MinorUnits
+		if err := got.UnmarshalText([]byte(text)); err != nil || got != want {
+			t.Errorf("%q: got %d, %v", text, got, err)
+		}
+	}
+
+	for _, text := range []string{"1.234", "abc", ".5", "--1", "1.2.3", "92233720368547758.08"} {
+		var got models.MinorUnits
+		if err := got.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("%q: parsed as %d", text, got)
+		}
+	}
+
+	if got := models.MinorUnits(math.MinInt64).Decimal(); got != "-92233720368547758.08" {
+		t.Errorf("MinInt64: got %q", got)
+	}
+
+	out, err := xml.Marshal(models.PaymentTotal{PaymentType: "cash", Amount: models.NewMoney(-305, "EUR")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<amount currency="EUR">-3.05</amount>`; !bytes.Contains(out, []byte(want)) {
+		t.Fatalf("got %s", out)
+	}
+}
+
+func TestListShiftsFilters(t *testing.T) {
+	var queries []string
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		io.WriteString(w, `<shifts xmlns="http://gsph.sub.com/cust/types">`)
+		for i, device := range []int{12, 13, 12} {
+			fmt.Fprintf(w, `<shift><id>%d</id><deviceId>%d</deviceId><start>2026-03-0%d</start><status>open</status><total>1.00</total></shift>`, i+1, device, i+1)
+		}
+		io.WriteString(w, `</shifts>`)
+	})
+
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	shifts, err := svc.ListShifts(context.Background(), models.ShiftFilter{DeviceID: 12, From: from})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shifts.Shift) != 1 || shifts.Shift[0].ID != 3 {
+		t.Fatalf("got %+v", shifts.Shift)
+	}
+	if queries[0] != "deviceId=12&from=2026-03-02T00%3A00%3A00Z" {
+		t.Fatalf("query %q", queries[0])
+	}
+}
+
+func TestListShiftsDateRange(t *testing.T) {
+	start := "2026-03-02T08:00:00"
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<shifts xmlns="http://gsph.sub.com/cust/types"><shift><id>1</id><deviceId>12</deviceId><start>%s</start><status>closed</status></shift></shifts>`, start)
+	})
+	ctx := context.Background()
+
+	// 08:00 in CET is 07:00 UTC, before a 07:30 UTC bound
+	cet := time.FixedZone("CET", 3600)
+	from := time.Date(2026, 3, 2, 7, 30, 0, 0, time.UTC)
+	for loc, want := range map[*time.Location]int{time.UTC: 1, cet: 0} {
+		shifts, err := svc.ListShifts(ctx, models.ShiftFilter{From: from.In(loc)})
+		if err != nil || len(shifts.Shift) != want {
+			t.Errorf("%s: got %v, %v", loc, shifts, err)
+		}
+	}
+
+	start = "not a date"
+	if _, err := svc.ListShifts(ctx, models.ShiftFilter{From: from}); err == nil {
+		t.Fatal("unparsable start was dropped silently")
+	}
+	if shifts, err := svc.ListShifts(ctx, models.ShiftFilter{DeviceID: 12}); err != nil || len(shifts.Shift) != 1 {
+		t.Fatalf("without a date range: got %v, %v", shifts, err)
+	}
+}
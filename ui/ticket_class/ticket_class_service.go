@@ -0,0 +1,70 @@
+package ticketclass
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	internalhttp "github.com/yassine-manai/go_zr_sdk/internal/http"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// Service handles ticket class operations
+type TicketClassService struct {
+	httpClient *internalhttp.Client
+	logger     logger.Logger
+}
+
+// NewTicketClassService creates a new ticket class service
+func NewTicketClassService(httpClient *internalhttp.Client, log logger.Logger) *TicketClassService {
+	return &TicketClassService{
+		httpClient: httpClient,
+		logger:     log,
+	}
+}
+
+// GetTicketClassList retrieves all ticket classes
+func (s *TicketClassService) GetTicketClassList(ctx context.Context) (*models.TicketClasses, error) {
+	s.logger.Info("getting all ticket classes from zr")
+
+	var result models.TicketClasses
+
+	err := s.httpClient.DoXMLRequest(
+		ctx,
+		http.MethodGet, models.TicketClassCustomerMedia,
+		nil, &result,
+	)
+
+	if err != nil {
+		s.logger.Error("failed to get ticket classes", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("ticket classes retrieved successfully", logger.Int("Count", len(result.TicketClass)))
+
+	return &result, nil
+}
+
+// GetTicketClassById retrieves a ticket class by ID
+func (s *TicketClassService) GetTicketClassById(ctx context.Context, ticketClassID int) (*models.TicketClass, error) {
+	s.logger.Info("getting ticket class", logger.Int("ticket_class_id", ticketClassID))
+
+	path := fmt.Sprintf(models.TicketClassCustomerMediaByID, ticketClassID)
+	var result models.TicketClass
+
+	err := s.httpClient.DoXMLRequest(
+		ctx,
+		http.MethodGet, path,
+		nil, &result,
+	)
+
+	if err != nil {
+		s.logger.Error("failed to get ticket class", logger.Int("ticket_class_id", ticketClassID), logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("ticket class retrieved successfully", logger.Int("ticket_class_id", ticketClassID), logger.String("name", result.Name))
+
+	return &result, nil
+}
@@ -0,0 +1,52 @@
+package ticketclass
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/yassine-manai/go_zr_sdk/internal/errors"
+	"github.com/yassine-manai/go_zr_sdk/internal/logger"
+	"github.com/yassine-manai/go_zr_sdk/internal/testutil"
+	"github.com/yassine-manai/go_zr_sdk/models"
+)
+
+// newTestService returns a TicketClassService talking to handler
+func newTestService(t *testing.T, handler http.HandlerFunc) *TicketClassService {
+	return NewTicketClassService(testutil.NewHTTPClient(t, handler), logger.NewNoOpLogger())
+}
+
+func TestTicketClasses(t *testing.T) {
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case models.TicketClassCustomerMedia:
+			io.WriteString(w, `<ticketClasses xmlns="http://gsph.sub.com/cust/types">`+
+				`<ticketClass><id>1</id><name>Day</name><tariffRef>T1</tariffRef></ticketClass>`+
+				`<ticketClass><id>4</id><name>Season</name><xValidFrom>2026-01-01</xValidFrom></ticketClass>`+
+				`</ticketClasses>`)
+		case "/CustomerMediaWebService/ticketclasses/4":
+			io.WriteString(w, `<ticketClass xmlns="http://gsph.sub.com/cust/types"><id>4</id><name>Season</name><tariffRef>T9</tariffRef></ticketClass>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	ctx := context.Background()
+
+	list, err := svc.GetTicketClassList(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.TicketClass) != 2 || list.TicketClass[0].TariffRef != "T1" || list.TicketClass[1].ValidFrom != "2026-01-01" {
+		t.Fatalf("got %+v", list.TicketClass)
+	}
+
+	class, err := svc.GetTicketClassById(ctx, 4)
+	if err != nil || class.Name != "Season" || class.TariffRef != "T9" {
+		t.Fatalf("got %+v, %v", class, err)
+	}
+
+	if _, err := svc.GetTicketClassById(ctx, 5); !errors.IsNotFoundError(err) {
+		t.Fatalf("unknown class: got %v", err)
+	}
+}